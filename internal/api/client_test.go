@@ -172,6 +172,71 @@ func TestClient_CreateTask(t *testing.T) {
 	}
 }
 
+func TestClient_CreateRecurringTask(t *testing.T) {
+	server := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/recurring/createRecurringTask" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+
+		var req models.CreateRecurringTaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Rule != "FREQ=WEEKLY;BYDAY=MO" {
+			t.Errorf("expected Rule 'FREQ=WEEKLY;BYDAY=MO', got '%s'", req.Rule)
+		}
+
+		response := models.RecurringTask{
+			ID:          "rec-123",
+			Description: req.Description,
+			Rule:        req.Rule,
+			StartDate:   req.StartDate,
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	client := setupTestClient(t, server.URL)
+	series, err := client.CreateRecurringTask(&models.CreateRecurringTaskRequest{
+		Description: "Weekly review",
+		Rule:        "FREQ=WEEKLY;BYDAY=MO",
+		StartDate:   "2024-01-15",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if series.ID != "rec-123" {
+		t.Errorf("expected ID 'rec-123', got '%s'", series.ID)
+	}
+}
+
+func TestClient_SkipRecurringOccurrence(t *testing.T) {
+	server := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/recurring/skipOccurrence" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		var req models.RecurringOccurrenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.RecurringID != "rec-123" || req.Date != "2024-01-22" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	})
+	defer server.Close()
+
+	client := setupTestClient(t, server.URL)
+	if err := client.SkipRecurringOccurrence("rec-123", "2024-01-22"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestClient_APIError(t *testing.T) {
 	server := setupTestServer(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// GetRecurringTasks retrieves all recurring task series
+func (c *Client) GetRecurringTasks() ([]models.RecurringTask, error) {
+	resp, err := c.Get("/v1/recurring/getRecurringTasks")
+	if err != nil {
+		return nil, err
+	}
+
+	var series []models.RecurringTask
+	if err := json.Unmarshal(resp, &series); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return series, nil
+}
+
+// GetRecurringTask retrieves a recurring task series by ID
+func (c *Client) GetRecurringTask(recurringID string) (*models.RecurringTask, error) {
+	path := fmt.Sprintf("/v1/recurring/getRecurringTask?recurringId=%s", url.QueryEscape(recurringID))
+	resp, err := c.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var series models.RecurringTask
+	if err := json.Unmarshal(resp, &series); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &series, nil
+}
+
+// CreateRecurringTask creates a new recurring task series
+func (c *Client) CreateRecurringTask(req *models.CreateRecurringTaskRequest) (*models.RecurringTask, error) {
+	resp, err := c.Post("/v1/recurring/createRecurringTask", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var series models.RecurringTask
+	if err := json.Unmarshal(resp, &series); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &series, nil
+}
+
+// SkipRecurringOccurrence removes a single occurrence from a series
+func (c *Client) SkipRecurringOccurrence(recurringID, date string) error {
+	req := &models.RecurringOccurrenceRequest{RecurringID: recurringID, Date: date}
+	_, err := c.Post("/v1/recurring/skipOccurrence", req)
+	return err
+}
+
+// RescheduleRecurringOccurrence moves a single occurrence of a series to
+// another date and optionally another start time
+func (c *Client) RescheduleRecurringOccurrence(req *models.RecurringOccurrenceRequest) (*models.Task, error) {
+	resp, err := c.Post("/v1/recurring/rescheduleOccurrence", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var task models.Task
+	if err := json.Unmarshal(resp, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &task, nil
+}
+
+// StopRecurringTask ends a series so that no occurrences are created on
+// or after endDate
+func (c *Client) StopRecurringTask(recurringID, endDate string) (*models.RecurringTask, error) {
+	req := &models.StopRecurringTaskRequest{RecurringID: recurringID, EndDate: endDate}
+	resp, err := c.Post("/v1/recurring/stopRecurringTask", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var series models.RecurringTask
+	if err := json.Unmarshal(resp, &series); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &series, nil
+}
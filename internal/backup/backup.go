@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// Version is the archive format version written by this CLI
+const Version = 1
+
+// Archive is a snapshot of an account
+type Archive struct {
+	Version    int                      `json:"version"`
+	CreatedAt  time.Time                `json:"created_at"`
+	From       string                   `json:"from"`
+	To         string                   `json:"to"`
+	User       *models.User             `json:"user,omitempty"`
+	Labels     []models.Label           `json:"labels"`
+	Lists      []models.List            `json:"lists"`
+	Braindump  []models.Task            `json:"braindump"`
+	ListTasks  map[string][]models.Task `json:"list_tasks"`
+	DatedTasks map[string][]models.Task `json:"dated_tasks"`
+}
+
+// Collect fetches everything needed for an archive. Dated tasks are
+// fetched for each of the given dates.
+func Collect(client *api.Client, dates []string, timeZone string) (*Archive, error) {
+	archive := &Archive{
+		Version:    Version,
+		CreatedAt:  time.Now().UTC(),
+		ListTasks:  make(map[string][]models.Task),
+		DatedTasks: make(map[string][]models.Task),
+	}
+	if len(dates) > 0 {
+		archive.From = dates[0]
+		archive.To = dates[len(dates)-1]
+	}
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	archive.User = user
+
+	if archive.Labels, err = client.GetLabels(); err != nil {
+		return nil, fmt.Errorf("failed to fetch labels: %w", err)
+	}
+	if archive.Lists, err = client.GetLists(); err != nil {
+		return nil, fmt.Errorf("failed to fetch lists: %w", err)
+	}
+	if archive.Braindump, err = client.GetBraindump(); err != nil {
+		return nil, fmt.Errorf("failed to fetch braindump: %w", err)
+	}
+
+	for _, list := range archive.Lists {
+		tasks, err := client.GetTasksByList(list.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks for list %s: %w", list.Title, err)
+		}
+		archive.ListTasks[list.ID] = tasks
+	}
+
+	for _, date := range dates {
+		tasks, err := client.GetTasksByDate(date, timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks for %s: %w", date, err)
+		}
+		if len(tasks) > 0 {
+			archive.DatedTasks[date] = tasks
+		}
+	}
+
+	return archive, nil
+}
+
+// Write encodes an archive as indented JSON
+func Write(w io.Writer, archive *Archive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(archive)
+}
+
+// Read decodes an archive and checks that its version is supported
+func Read(r io.Reader) (*Archive, error) {
+	var archive Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("failed to parse archive: %w", err)
+	}
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d (supported: 1-%d)", archive.Version, Version)
+	}
+	return &archive, nil
+}
+
+// Tasks returns every task in the archive once, in a stable order
+func (a *Archive) Tasks() []models.Task {
+	seen := make(map[string]bool)
+	var tasks []models.Task
+
+	add := func(list []models.Task) {
+		for _, task := range list {
+			if seen[task.ID] {
+				continue
+			}
+			seen[task.ID] = true
+			tasks = append(tasks, task)
+		}
+	}
+
+	add(a.Braindump)
+	for _, list := range a.Lists {
+		add(a.ListTasks[list.ID])
+	}
+	for _, date := range sortedKeys(a.DatedTasks) {
+		add(a.DatedTasks[date])
+	}
+	return tasks
+}
@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestReadWrite_RoundTrip(t *testing.T) {
+	archive := &Archive{
+		Version: Version,
+		From:    "2024-01-01",
+		To:      "2024-01-31",
+		Labels:  []models.Label{{ID: "label-1", Name: "Work", Color: "#FF0000"}},
+		DatedTasks: map[string][]models.Task{
+			"2024-01-15": {{ID: "task-1", Description: "Dated task"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, archive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Labels[0].Name != "Work" {
+		t.Errorf("expected label 'Work', got '%s'", decoded.Labels[0].Name)
+	}
+	if len(decoded.Tasks()) != 1 {
+		t.Errorf("expected 1 task, got %d", len(decoded.Tasks()))
+	}
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"version": 99}`))
+	if err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	archive := &Archive{
+		Version: Version,
+		Labels: []models.Label{
+			{ID: "old-work", Name: "Work", Color: "#FF0000"},
+			{ID: "old-home", Name: "Home", Color: "#00FF00"},
+		},
+		Lists: []models.List{
+			{ID: "old-inbox", Title: "Inbox"},
+			{ID: "old-gone", Title: "Gone"},
+		},
+		Braindump: []models.Task{
+			{ID: "task-1", Description: "Already there"},
+			{ID: "task-2", Description: "New idea", Label: strPtr("old-work"), ListID: strPtr("old-inbox")},
+		},
+		ListTasks: map[string][]models.Task{
+			"old-inbox": {{ID: "task-2", Description: "New idea", Label: strPtr("old-work"), ListID: strPtr("old-inbox")}},
+		},
+		DatedTasks: map[string][]models.Task{
+			"2024-01-15": {
+				{ID: "task-3", Description: "Standup", Date: json.RawMessage(`"2024-01-15"`), Recurring: true},
+			},
+		},
+	}
+	current := &Archive{
+		Labels:    []models.Label{{ID: "new-work", Name: "work"}},
+		Lists:     []models.List{{ID: "new-inbox", Title: "Inbox"}},
+		Braindump: []models.Task{{ID: "other", Description: "Already there"}},
+	}
+
+	plan, err := NewPlan(archive, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if plan.LabelIDs["old-work"] != "new-work" {
+		t.Errorf("expected label mapping to 'new-work', got '%s'", plan.LabelIDs["old-work"])
+	}
+	if len(plan.LabelsToCreate) != 1 || plan.LabelsToCreate[0].Name != "Home" {
+		t.Errorf("expected to create label 'Home', got %+v", plan.LabelsToCreate)
+	}
+	if len(plan.MissingLists) != 1 || plan.MissingLists[0].Title != "Gone" {
+		t.Errorf("expected missing list 'Gone', got %+v", plan.MissingLists)
+	}
+	if len(plan.TasksToCreate) != 1 || plan.TasksToCreate[0].ID != "task-2" {
+		t.Fatalf("expected to create task-2 once, got %+v", plan.TasksToCreate)
+	}
+	if len(plan.ExistingTasks) != 1 {
+		t.Errorf("expected 1 existing task, got %d", len(plan.ExistingTasks))
+	}
+	if len(plan.SkippedTasks) != 1 {
+		t.Errorf("expected 1 skipped recurring task, got %d", len(plan.SkippedTasks))
+	}
+
+	req := plan.CreateRequest(&plan.TasksToCreate[0])
+	if req.Label == nil || *req.Label != "new-work" {
+		t.Errorf("expected mapped label 'new-work', got %v", req.Label)
+	}
+	if req.ListID == nil || *req.ListID != "new-inbox" {
+		t.Errorf("expected mapped list 'new-inbox', got %v", req.ListID)
+	}
+}
+
+func TestNewPlan_AmbiguousLabel(t *testing.T) {
+	archive := &Archive{Labels: []models.Label{{ID: "old-work", Name: "Deep Work"}}}
+	current := &Archive{Labels: []models.Label{
+		{ID: "new-1", Name: "deep work"},
+		{ID: "new-2", Name: "deep-work"},
+	}}
+
+	if _, err := NewPlan(archive, current); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguous label error, got %v", err)
+	}
+}
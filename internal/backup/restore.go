@@ -0,0 +1,170 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+)
+
+// Plan describes the changes needed to replay an archive into an account
+type Plan struct {
+	// LabelIDs maps archived label IDs to label IDs in the target account
+	LabelIDs map[string]string
+	// ListIDs maps archived list IDs to list IDs in the target account
+	ListIDs map[string]string
+
+	LabelsToCreate []models.Label
+	MissingLists   []models.List
+	TasksToCreate  []models.Task
+	ExistingTasks  []models.Task
+	SkippedTasks   []models.Task
+}
+
+// NewPlan compares an archive with the current state of an account.
+// Labels and lists are matched by name as described in package resolve,
+// tasks by description, date and start time. Recurring instances are
+// skipped because they belong to a series rather than being standalone
+// tasks. An archived name matching several current ones is an error.
+func NewPlan(archive, current *Archive) (*Plan, error) {
+	plan := &Plan{
+		LabelIDs: make(map[string]string),
+		ListIDs:  make(map[string]string),
+	}
+
+	names := models.Names{
+		Labels: make(map[string]string, len(current.Labels)),
+		Lists:  make(map[string]string, len(current.Lists)),
+	}
+	for _, label := range current.Labels {
+		names.Labels[label.ID] = label.Name
+	}
+	for _, list := range current.Lists {
+		names.Lists[list.ID] = list.Title
+	}
+
+	for _, label := range archive.Labels {
+		id, err := resolve.LabelID(names, label.Name)
+		switch {
+		case err == nil:
+			plan.LabelIDs[label.ID] = id
+		case errors.Is(err, resolve.ErrNotFound):
+			plan.LabelsToCreate = append(plan.LabelsToCreate, label)
+		default:
+			return nil, err
+		}
+	}
+
+	for _, list := range archive.Lists {
+		id, err := resolve.ListID(names, list.Title)
+		switch {
+		case err == nil:
+			plan.ListIDs[list.ID] = id
+		case errors.Is(err, resolve.ErrNotFound):
+			plan.MissingLists = append(plan.MissingLists, list)
+		default:
+			return nil, err
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, task := range current.Tasks() {
+		existing[taskKey(&task)] = true
+	}
+	for _, task := range archive.Tasks() {
+		switch {
+		case task.Recurring || task.RecurringID != nil:
+			plan.SkippedTasks = append(plan.SkippedTasks, task)
+		case existing[taskKey(&task)]:
+			plan.ExistingTasks = append(plan.ExistingTasks, task)
+		default:
+			existing[taskKey(&task)] = true
+			plan.TasksToCreate = append(plan.TasksToCreate, task)
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply creates the planned labels and tasks. It returns the tasks that
+// were created before any error occurred.
+func Apply(client *api.Client, plan *Plan) ([]models.Task, error) {
+	for _, label := range plan.LabelsToCreate {
+		created, err := client.CreateLabel(&models.CreateLabelRequest{
+			Name:  label.Name,
+			Color: label.Color,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create label %s: %w", label.Name, err)
+		}
+		plan.LabelIDs[label.ID] = created.ID
+	}
+
+	var created []models.Task
+	for i := range plan.TasksToCreate {
+		task, err := client.CreateTask(plan.CreateRequest(&plan.TasksToCreate[i]))
+		if err != nil {
+			return created, fmt.Errorf("failed to create task %q: %w", plan.TasksToCreate[i].Description, err)
+		}
+		if plan.TasksToCreate[i].Complete {
+			if task, err = client.MarkTaskComplete(task.ID); err != nil {
+				return created, fmt.Errorf("failed to complete task %q: %w", plan.TasksToCreate[i].Description, err)
+			}
+		}
+		created = append(created, *task)
+	}
+
+	return created, nil
+}
+
+// CreateRequest builds the request that recreates an archived task with
+// its label and list IDs mapped to the target account
+func (p *Plan) CreateRequest(task *models.Task) *models.CreateTaskRequest {
+	req := &models.CreateTaskRequest{
+		Description:   task.Description,
+		EstimatedTime: task.EstimatedTime,
+		Priority:      task.Priority,
+	}
+
+	if date := task.GetDateString(); date != "" {
+		req.Date = &date
+	}
+	if start := task.GetStartString(); start != "" {
+		req.Start = &start
+	}
+	if due := task.GetDueDateString(); due != "" {
+		req.DueDate = &due
+	}
+	if task.Label != nil {
+		if id, ok := p.LabelIDs[*task.Label]; ok {
+			req.Label = &id
+		}
+	}
+	if task.ListID != nil {
+		if id, ok := p.ListIDs[*task.ListID]; ok {
+			req.ListID = &id
+		}
+	}
+	return req
+}
+
+func taskKey(task *models.Task) string {
+	return strings.Join([]string{
+		strings.ToLower(strings.TrimSpace(task.Description)),
+		task.GetDateString(),
+		task.GetStartString(),
+	}, "\x00")
+}
+
+func sortedKeys(m map[string][]models.Task) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,53 @@
+package chart
+
+import (
+	"strings"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// eighths are the partial blocks used for the end of a bar
+var eighths = []rune(" ▏▎▍▌▋▊▉")
+
+// Sparkline draws one character per value, scaled to the largest value.
+// Zero is always drawn as the lowest block so gaps stay visible.
+func Sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		if peak == 0 || v <= 0 {
+			b.WriteRune(sparks[0])
+			continue
+		}
+		i := (v*(len(sparks)-1) + peak - 1) / peak
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+// Bar draws a horizontal bar for value on a scale where peak fills width
+// cells, using partial blocks for eighths of a cell
+func Bar(value, peak, width int) string {
+	if value <= 0 || peak <= 0 || width <= 0 {
+		return ""
+	}
+	if value > peak {
+		value = peak
+	}
+
+	units := value * width * 8 / peak
+	if units == 0 {
+		units = 1
+	}
+	bar := strings.Repeat("█", units/8)
+	if rest := units % 8; rest > 0 {
+		bar += string(eighths[rest])
+	}
+	return bar
+}
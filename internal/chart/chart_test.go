@@ -0,0 +1,46 @@
+package chart
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{[]int{0, 1, 2, 4, 8}, "▁▂▃▅█"},
+		{[]int{0, 0}, "▁▁"},
+		{[]int{3, 3}, "██"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		value, max, width int
+		want              string
+	}{
+		{10, 10, 4, "████"},
+		{5, 10, 4, "██"},
+		{1, 10, 4, "▍"},
+		{1, 1000, 4, "▏"},
+		{0, 10, 4, ""},
+		{20, 10, 4, "████"},
+	}
+	for _, tt := range tests {
+		if got := Bar(tt.value, tt.max, tt.width); got != tt.want {
+			t.Errorf("Bar(%d, %d, %d) = %q, want %q", tt.value, tt.max, tt.width, got, tt.want)
+		}
+	}
+
+	if n := utf8.RuneCountInString(Bar(7, 9, 20)); n > 20 {
+		t.Errorf("bar wider than its width: %d", n)
+	}
+}
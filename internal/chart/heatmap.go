@@ -0,0 +1,98 @@
+package chart
+
+import (
+	"strings"
+	"time"
+)
+
+// Heatmap levels
+const (
+	// LevelNone marks days that have nothing to show, drawn blank
+	LevelNone = -1
+	// LevelMax is the darkest level
+	LevelMax = 4
+)
+
+var heatGlyphs = []string{"·", "░", "▒", "▓", "█"}
+
+var weekdayLabels = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// Heatmap draws a GitHub-style grid with one column per week and one row
+// per weekday, Monday first, above a row of month names. level returns
+// LevelNone or 0..LevelMax for each day between from and to.
+func Heatmap(from, to time.Time, level func(day time.Time) int) []string {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return nil
+	}
+
+	first := from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+	weeks := int(to.Sub(first).Hours()/24)/7 + 1
+
+	// Month names go above the week they start in. A name that would run
+	// into the next one, as after a partial first week, is dropped.
+	type mark struct {
+		col   int
+		label string
+	}
+	var marks []mark
+	lastMonth := time.Month(0)
+	for w := 0; w < weeks; w++ {
+		day := first.AddDate(0, 0, 7*w)
+		if day.Before(from) {
+			day = from
+		}
+		if day.Month() == lastMonth {
+			continue
+		}
+		lastMonth = day.Month()
+		m := mark{col: 4 + 2*w, label: day.Format("Jan")}
+		if n := len(marks); n > 0 && marks[n-1].col+len(marks[n-1].label) >= m.col {
+			marks = marks[:n-1]
+		}
+		marks = append(marks, m)
+	}
+
+	var header strings.Builder
+	for _, m := range marks {
+		header.WriteString(strings.Repeat(" ", m.col-header.Len()))
+		header.WriteString(m.label)
+	}
+
+	lines := []string{header.String()}
+	for wd := 0; wd < 7; wd++ {
+		var b strings.Builder
+		b.WriteString(weekdayLabels[wd])
+		b.WriteString(" ")
+		for w := 0; w < weeks; w++ {
+			day := first.AddDate(0, 0, 7*w+wd)
+			glyph := " "
+			if !day.Before(from) && !day.After(to) {
+				if l := level(day); l >= 0 {
+					if l > LevelMax {
+						l = LevelMax
+					}
+					glyph = heatGlyphs[l]
+				}
+			}
+			b.WriteString(glyph)
+			if w < weeks-1 {
+				b.WriteString(" ")
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+// Level scales n out of total onto 1..LevelMax, or 0 when n is zero
+func Level(n, total int) int {
+	if n <= 0 || total <= 0 {
+		return 0
+	}
+	if n >= total {
+		return LevelMax
+	}
+	return 1 + n*(LevelMax-1)/total
+}
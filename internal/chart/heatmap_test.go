@@ -0,0 +1,61 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeatmap(t *testing.T) {
+	from := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC) // Wednesday
+	to := time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC)    // Tuesday
+
+	lines := Heatmap(from, to, func(day time.Time) int {
+		switch day.Day() {
+		case 31:
+			return LevelMax
+		case 1:
+			return 0
+		case 2:
+			return LevelNone
+		}
+		return 2
+	})
+
+	expected := []string{
+		"      Feb",
+		"Mon   ▒",
+		"Tue   ▒",
+		"Wed █",
+		"Thu ·",
+		"Fri",
+		"Sat ▒",
+		"Sun ▒",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected heatmap:\n%s\nexpected:\n%s", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestHeatmapEmptyRange(t *testing.T) {
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if lines := Heatmap(from, from.AddDate(0, 0, -1), func(time.Time) int { return 0 }); lines != nil {
+		t.Errorf("expected no lines, got %q", lines)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct{ n, total, want int }{
+		{0, 3, 0},
+		{1, 3, 2},
+		{2, 3, 3},
+		{3, 3, LevelMax},
+		{1, 1, LevelMax},
+		{1, 10, 1},
+	}
+	for _, tt := range tests {
+		if got := Level(tt.n, tt.total); got != tt.want {
+			t.Errorf("Level(%d, %d) = %d, want %d", tt.n, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/backup"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Export the account into a JSON archive",
+	Long:  "Exports user, labels, lists, braindump, per-list tasks and the dated tasks in a date range into a versioned JSON archive.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		output, _ := cmd.Flags().GetString("output")
+
+		start, end, err := dateutil.ParseRange(from, to, time.Now())
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		archive, err := backup.Collect(client, dateutil.Range(start, end), timeZone)
+		if err != nil {
+			return err
+		}
+
+		if output == "" || output == "-" {
+			return backup.Write(os.Stdout, archive)
+		}
+
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create backup file: %w", err)
+		}
+
+		// The archive holds the whole account, so unlike writeOutput the
+		// file is only readable by the user. A failed close can mean a
+		// truncated backup and must not be reported as success.
+		if err := backup.Write(f, archive); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+
+		fmt.Printf("Backed up %d labels, %d lists and %d tasks to %s\n",
+			len(archive.Labels), len(archive.Lists), len(archive.Tasks()), output)
+		return nil
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Replay a backup archive into the account",
+	Long:  "Creates missing labels and tasks from a backup archive. Labels and lists are matched by name and their IDs remapped; tasks that already exist are skipped.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open backup file: %w", err)
+		}
+		defer f.Close()
+
+		archive, err := backup.Read(f)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		var dates []string
+		if archive.From != "" && archive.To != "" {
+			start, end, err := dateutil.ParseRange(archive.From, archive.To, time.Now())
+			if err != nil {
+				return err
+			}
+			dates = dateutil.Range(start, end)
+		}
+
+		current, err := backup.Collect(client, dates, timeZone)
+		if err != nil {
+			return err
+		}
+
+		plan, err := backup.NewPlan(archive, current)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return outputRestorePlan(plan)
+		}
+
+		created, err := backup.Apply(client, plan)
+		if err != nil {
+			if len(created) > 0 {
+				fmt.Fprintf(os.Stderr, "Restored %d task(s) before the error\n", len(created))
+			}
+			return err
+		}
+
+		if IsJSONOutput() {
+			return outputTasks(created)
+		}
+
+		fmt.Printf("Restored %d label(s) and %d task(s), skipped %d existing\n",
+			len(plan.LabelsToCreate), len(created), len(plan.ExistingTasks))
+		for _, list := range plan.MissingLists {
+			fmt.Printf("Warning: list %q does not exist, its tasks were restored without a list\n", list.Title)
+		}
+		return nil
+	},
+}
+
+func init() {
+	backupCmd.Flags().String("from", "-30d", "Start of the dated task range")
+	backupCmd.Flags().String("to", "+30d", "End of the dated task range")
+	backupCmd.Flags().String("timezone", "", "Timezone (e.g., America/New_York)")
+	backupCmd.Flags().StringP("output", "o", "", "Write the archive to a file instead of stdout")
+
+	restoreCmd.Flags().Bool("dry-run", false, "Show what would change without modifying the account")
+	restoreCmd.Flags().String("timezone", "", "Timezone (e.g., America/New_York)")
+}
+
+func outputRestorePlan(plan *backup.Plan) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, label := range plan.LabelsToCreate {
+		fmt.Printf("+ label %s (%s)\n", label.Name, label.Color)
+	}
+	for _, list := range plan.MissingLists {
+		fmt.Printf("! list %s is missing and cannot be created, its tasks will have no list\n", list.Title)
+	}
+	for _, task := range plan.TasksToCreate {
+		fmt.Printf("+ task %s\n", describeRestoreTask(&task))
+	}
+	for _, task := range plan.ExistingTasks {
+		fmt.Printf("= task %s\n", describeRestoreTask(&task))
+	}
+	for _, task := range plan.SkippedTasks {
+		fmt.Printf("- task %s (recurring, skipped)\n", describeRestoreTask(&task))
+	}
+
+	fmt.Printf("\n%d label(s) and %d task(s) to create, %d task(s) already present\n",
+		len(plan.LabelsToCreate), len(plan.TasksToCreate), len(plan.ExistingTasks))
+	return nil
+}
+
+func describeRestoreTask(task *models.Task) string {
+	desc := task.Description
+	if date := task.GetDateString(); date != "" {
+		desc += " [" + date + "]"
+	}
+	if task.Complete {
+		desc += " (done)"
+	}
+	return desc
+}
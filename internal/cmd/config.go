@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/goldie/ellie-cli/internal/config"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/planner"
 	"github.com/spf13/cobra"
 )
 
@@ -41,6 +44,11 @@ var showConfigCmd = &cobra.Command{
 
 		fmt.Println("Configuration:")
 		fmt.Printf("  Base URL: %s\n", cfg.BaseURL)
+		if cfg.ScanLabel != "" {
+			fmt.Printf("  Scan label: %s\n", cfg.ScanLabel)
+		}
+
+		fmt.Printf("  Capacity: %s\n", formatCapacity(loadCapacity()))
 
 		if cfg.APIKey != "" {
 			// Mask the API key, showing only first 8 and last 4 characters
@@ -80,10 +88,81 @@ var setBaseURLCmd = &cobra.Command{
 	},
 }
 
+var setScanLabelCmd = &cobra.Command{
+	Use:   "set-scan-label <label>",
+	Short: "Set the label for tasks created by 'ellie scan todos'",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		label := strings.TrimSpace(args[0])
+		if label == "" {
+			return fmt.Errorf("label cannot be empty")
+		}
+
+		if err := config.SetScanLabel(label); err != nil {
+			return fmt.Errorf("failed to save scan label: %w", err)
+		}
+
+		fmt.Printf("Scan label set to: %s\n", label)
+		return nil
+	},
+}
+
+var setCapacityCmd = &cobra.Command{
+	Use:   "set-capacity <days> <duration>",
+	Short: "Set the working time per day used by 'ellie plan capacity'",
+	Long:  "Sets the working time for the given weekdays, e.g. 'set-capacity weekdays 7h30m' or 'set-capacity fri 4h'. Days are mon-sun, ranges like mon-thu, weekdays, weekend or all. The default is 8h on weekdays and none on weekends.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		weekdays, err := planner.ParseWeekdays(args[0])
+		if err != nil {
+			return err
+		}
+		d, err := time.ParseDuration(args[1])
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid duration %q: expected e.g. 8h or 7h30m", args[1])
+		}
+
+		days := make([]string, len(weekdays))
+		for i, day := range weekdays {
+			days[i] = planner.DayNames[day]
+		}
+		if err := config.SetCapacity(days, int(d/time.Minute)); err != nil {
+			return fmt.Errorf("failed to save capacity: %w", err)
+		}
+
+		fmt.Printf("Capacity set to: %s\n", formatCapacity(loadCapacity()))
+		return nil
+	},
+}
+
 func init() {
 	configCmd.AddCommand(setAPIKeyCmd)
 	configCmd.AddCommand(showConfigCmd)
 	configCmd.AddCommand(setBaseURLCmd)
+	configCmd.AddCommand(setScanLabelCmd)
+	configCmd.AddCommand(setCapacityCmd)
+}
+
+// loadCapacity returns the configured working minutes per weekday,
+// falling back to planner.DefaultCapacity
+func loadCapacity() planner.Capacity {
+	capacity := planner.DefaultCapacity
+	for i, day := range planner.DayNames {
+		if minutes, ok := config.GetCapacity(day); ok {
+			capacity[i] = minutes
+		}
+	}
+	return capacity
+}
+
+// formatCapacity lists the capacity from Monday to Sunday
+func formatCapacity(capacity planner.Capacity) string {
+	parts := make([]string, 0, 7)
+	for i := 1; i <= 7; i++ {
+		day := i % 7
+		parts = append(parts, planner.DayNames[day]+" "+dateutil.FormatMinutes(capacity[day]))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func maskAPIKey(key string) string {
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/ical"
+	"github.com/goldie/ellie-cli/internal/markdown"
+	"github.com/goldie/ellie-cli/internal/org"
+	"github.com/goldie/ellie-cli/internal/taskcsv"
+	"github.com/goldie/ellie-cli/internal/taskwarrior"
+	"github.com/goldie/ellie-cli/internal/todotxt"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tasks to other formats",
+}
+
+var exportICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export tasks as an iCalendar file",
+	Long:  "Exports the agenda for a date range as iCalendar. Tasks with a start time become events, other tasks become to-dos.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		output, _ := cmd.Flags().GetString("output")
+		todos, _ := cmd.Flags().GetBool("todos")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		start, end, err := dateutil.ParseRange(from, to, time.Now().In(loc))
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dateutil.Range(start, end))
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).labelMap()
+		if err != nil {
+			return err
+		}
+
+		cal := ical.FromTasks(tasks, ical.Options{
+			Location:   loc,
+			LabelNames: names,
+			Todos:      todos,
+			Name:       "Ellie",
+		})
+
+		return writeOutput(output, func(w io.Writer) error {
+			if err := cal.Encode(w); err != nil {
+				return fmt.Errorf("failed to write calendar: %w", err)
+			}
+			return nil
+		})
+	},
+}
+
+var exportTodoTxtCmd = &cobra.Command{
+	Use:   "todotxt",
+	Short: "Export tasks in todo.txt format",
+	Long:  "Exports tasks as todo.txt lines. Priorities map to (A)-(D), lists to +projects, labels to @contexts, the scheduled date to t: and the due date to due:.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		res := newResolver(client)
+
+		tasks, err := collectTasks(cmd, client, res)
+		if err != nil {
+			return err
+		}
+
+		names, err := res.names()
+		if err != nil {
+			return err
+		}
+
+		items := make([]todotxt.Item, 0, len(tasks))
+		for i := range tasks {
+			items = append(items, todotxt.FromTask(&tasks[i], names))
+		}
+
+		return writeOutput(output, func(w io.Writer) error {
+			return todotxt.Write(w, items)
+		})
+	},
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Export tasks as CSV",
+	Long:  "Exports tasks with the columns " + strings.Join(taskcsv.Fields, ", ") + ". The file can be edited in a spreadsheet and imported again with 'ellie import csv'.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		dateFormat, _ := cmd.Flags().GetString("date-format")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		res := newResolver(client)
+
+		tasks, err := collectTasks(cmd, client, res)
+		if err != nil {
+			return err
+		}
+
+		names, err := res.names()
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(output, func(w io.Writer) error {
+			return taskcsv.Write(w, tasks, taskcsv.Options{Names: names, DateLayout: dateFormat, Location: loc})
+		})
+	},
+}
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Export a day's agenda as a Markdown checklist",
+	Long: `Renders the agenda for a date as a Markdown checklist for daily notes (Obsidian, Logseq, ...).
+
+With --note the checklist is written into the file between ` + markdown.BeginMarker + ` and ` + markdown.EndMarker + `
+markers; the rest of the note is left untouched. Each line carries the task ID and its
+exported state in an HTML comment so that 'ellie sync markdown' can apply ticked boxes and
+edits back without undoing changes made in the app since.
+
+A custom --template (Go text/template) receives .Date and .Items; each item has .Line,
+.Task, .Done, .Time, .Estimate and .Tags.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		templateFile, _ := cmd.Flags().GetString("template")
+		notePath, _ := cmd.Flags().GetString("note")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		day, err := dateutil.Parse(date, time.Now().In(loc))
+		if err != nil {
+			return err
+		}
+
+		var tmpl string
+		if templateFile != "" {
+			data, err := os.ReadFile(templateFile)
+			if err != nil {
+				return fmt.Errorf("failed to read template: %w", err)
+			}
+			tmpl = string(data)
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, []string{dateutil.Format(day)})
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).labelMap()
+		if err != nil {
+			return err
+		}
+
+		block, err := markdown.Render(dateutil.Format(day), tasks, markdown.Options{
+			Location:   loc,
+			LabelNames: names,
+			Template:   tmpl,
+		})
+		if err != nil {
+			return err
+		}
+
+		if notePath == "" {
+			fmt.Print(block)
+			return nil
+		}
+
+		existing, err := os.ReadFile(notePath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read note: %w", err)
+		}
+
+		merged := markdown.Merge(string(existing), block)
+		if err := os.WriteFile(notePath, []byte(merged), 0644); err != nil {
+			return fmt.Errorf("failed to write note: %w", err)
+		}
+
+		fmt.Printf("Wrote %d task(s) to %s\n", len(tasks), notePath)
+		return nil
+	},
+}
+
+var exportOrgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Export tasks as org-mode headings",
+	Long:  "Exports tasks as TODO/DONE headings with SCHEDULED/DEADLINE timestamps, [#A]-[#D] priorities, labels as tags and an :ELLIE_ID: property so 'ellie import org' can update them later.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		res := newResolver(client)
+
+		tasks, err := collectTasks(cmd, client, res)
+		if err != nil {
+			return err
+		}
+
+		names, err := res.names()
+		if err != nil {
+			return err
+		}
+
+		headings := make([]org.Heading, 0, len(tasks))
+		for i := range tasks {
+			headings = append(headings, org.FromTask(&tasks[i], names, loc))
+		}
+
+		return writeOutput(output, func(w io.Writer) error {
+			return org.Write(w, headings)
+		})
+	},
+}
+
+var exportTaskwarriorCmd = &cobra.Command{
+	Use:   "taskwarrior",
+	Short: "Export tasks as Taskwarrior JSON",
+	Long:  "Exports tasks as a JSON array that 'task import' accepts. Priorities map to H/M/L, lists to projects, labels to tags, the date and start time to scheduled and the due date to due. UUIDs are derived from task IDs, so re-exporting updates tasks instead of duplicating them.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		res := newResolver(client)
+
+		tasks, err := collectTasks(cmd, client, res)
+		if err != nil {
+			return err
+		}
+
+		names, err := res.names()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		exported := make([]taskwarrior.Task, 0, len(tasks))
+		for i := range tasks {
+			exported = append(exported, taskwarrior.FromTask(&tasks[i], names, loc, now))
+		}
+
+		return writeOutput(output, func(w io.Writer) error {
+			return taskwarrior.Write(w, exported)
+		})
+	},
+}
+
+func init() {
+	exportICSCmd.Flags().String("from", "today", "Start date (YYYY-MM-DD, today, -7d, ...)")
+	exportICSCmd.Flags().String("to", "+7d", "End date (YYYY-MM-DD, today, +7d, ...)")
+	exportICSCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	exportICSCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	exportICSCmd.Flags().Bool("todos", false, "Export every task as a to-do, including timed ones")
+	exportCmd.AddCommand(exportICSCmd)
+
+	addTaskSourceFlags(exportTodoTxtCmd)
+	exportTodoTxtCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	exportCmd.AddCommand(exportTodoTxtCmd)
+
+	addTaskSourceFlags(exportCSVCmd)
+	exportCSVCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	exportCSVCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	exportCSVCmd.Flags().String("date-format", "2006-01-02", "Go time layout of date columns, e.g. 01/02/2006")
+	exportCmd.AddCommand(exportCSVCmd)
+
+	exportMarkdownCmd.Flags().String("date", "today", "Date of the agenda (YYYY-MM-DD, today, ...)")
+	exportMarkdownCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	exportMarkdownCmd.Flags().String("template", "", "Go template file for the note block")
+	exportMarkdownCmd.Flags().String("note", "", "Merge into this note file between ellie markers")
+	exportCmd.AddCommand(exportMarkdownCmd)
+
+	addTaskSourceFlags(exportOrgCmd)
+	exportOrgCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	exportOrgCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	exportCmd.AddCommand(exportOrgCmd)
+
+	addTaskSourceFlags(exportTaskwarriorCmd)
+	exportTaskwarriorCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	exportTaskwarriorCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	exportCmd.AddCommand(exportTaskwarriorCmd)
+}
+
+// loadLocation resolves a --timezone flag, defaulting to the local zone
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// fetchAgenda collects the full agenda, including recurring instances,
+// for each date. Tasks without a date are stamped with the date they
+// were fetched for, and instances seen twice are dropped.
+func fetchAgenda(client *api.Client, dates []string) ([]models.Task, error) {
+	seen := make(map[string]bool)
+	var all []models.Task
+
+	for _, date := range dates {
+		tasks, err := client.GetTasksForDate(date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks for %s: %w", date, err)
+		}
+
+		for _, task := range tasks {
+			if task.GetDateString() == "" {
+				task.Date, _ = json.Marshal(date)
+			}
+			key := task.ID + "/" + task.GetDateString()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			all = append(all, task)
+		}
+	}
+
+	return all, nil
+}
+
+// addTaskSourceFlags registers the flags that select the tasks an export covers
+func addTaskSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from", "today", "Start date (YYYY-MM-DD, today, -7d, ...)")
+	cmd.Flags().String("to", "today", "End date (YYYY-MM-DD, today, +7d, ...)")
+	cmd.Flags().Bool("braindump", false, "Include unscheduled tasks")
+	cmd.Flags().String("list", "", "Export the tasks of a list (name or ID) instead of a date range")
+}
+
+// collectTasks fetches the tasks selected by the task source flags
+func collectTasks(cmd *cobra.Command, client *api.Client, res *resolver) ([]models.Task, error) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	braindump, _ := cmd.Flags().GetBool("braindump")
+	list, _ := cmd.Flags().GetString("list")
+
+	if list != "" {
+		listID, err := res.listID(list)
+		if err != nil {
+			return nil, err
+		}
+		return client.GetTasksByList(listID)
+	}
+
+	start, end, err := dateutil.ParseRange(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := fetchAgenda(client, dateutil.Range(start, end))
+	if err != nil {
+		return nil, err
+	}
+
+	if braindump {
+		unscheduled, err := client.GetBraindump()
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, unscheduled...)
+	}
+	return tasks, nil
+}
+
+// createOutput opens the file given by an --output flag, or stdout when
+// the flag is empty or "-"
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return f, nil
+}
+
+// writeOutput writes to the file given by an --output flag, or stdout.
+// Errors from closing the file are returned since they can mean the
+// output is incomplete.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	w, err := createOutput(path)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/term"
+	"github.com/goldie/ellie-cli/internal/timer"
+	"github.com/spf13/cobra"
+)
+
+var focusCmd = &cobra.Command{
+	Use:   "focus <task-id>",
+	Short: "Run pomodoro focus rounds on a task",
+	Long:  "Counts down work rounds and breaks in the terminal, ringing the bell when each phase ends. Every completed work round is added to the task's actual time; a round interrupted with Ctrl-C is not. Use 'ellie focus status' to show the running session in a status bar.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		work, _ := cmd.Flags().GetDuration("work")
+		breakTime, _ := cmd.Flags().GetDuration("break")
+		rounds, _ := cmd.Flags().GetInt("rounds")
+		complete, _ := cmd.Flags().GetBool("complete")
+		plain, _ := cmd.Flags().GetBool("plain")
+		noBell, _ := cmd.Flags().GetBool("no-bell")
+
+		if work <= 0 {
+			return fmt.Errorf("--work must be positive")
+		}
+		if breakTime < 0 {
+			return fmt.Errorf("--break cannot be negative")
+		}
+		if rounds < 1 {
+			return fmt.Errorf("--rounds must be at least 1")
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		task, err := client.GetTask(args[0])
+		if err != nil {
+			return err
+		}
+
+		store, err := timer.Open()
+		if err != nil {
+			return err
+		}
+
+		interactive := !plain && term.IsTerminal(os.Stdout)
+		out := &focusOutput{interactive: interactive, bell: !noBell && interactive}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		completed := 0
+		var logged time.Duration
+		var saveErr error
+		interrupted := false
+
+		for round := 1; round <= rounds && !interrupted; round++ {
+			now := time.Now()
+			err := store.Update(func(st *timer.State) error {
+				return st.BeginFocus(task.ID, task.Description, round, rounds, work, now)
+			})
+			if err != nil {
+				return err
+			}
+
+			out.phase(timer.PhaseWork, round, rounds, task.Description, now.Add(work))
+			if interrupted = countdown(now.Add(work), signals, out); interrupted {
+				break
+			}
+
+			pause := breakTime
+			if round == rounds {
+				pause = 0
+			}
+			err = store.Update(func(st *timer.State) error {
+				entry, err := st.BeginBreak(pause, time.Now())
+				if err == nil {
+					logged += time.Duration(entry.Seconds) * time.Second
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			completed++
+			out.ring()
+
+			if _, err := saveTimerEntries(client, store, "focus"); err != nil {
+				// The round stays in the timer log and is retried by 'ellie timer stop'
+				saveErr = err
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+
+			if pause > 0 {
+				breakEnd := time.Now().Add(pause)
+				out.phase(timer.PhaseBreak, round, rounds, task.Description, breakEnd)
+				if interrupted = countdown(breakEnd, signals, out); !interrupted {
+					out.ring()
+				}
+			}
+		}
+
+		if err := store.Update(func(st *timer.State) error {
+			st.EndFocus()
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		completedTask := false
+		if complete && !interrupted && saveErr == nil {
+			preImages, err := snapshotTasks(client, task.ID)
+			if err != nil {
+				return err
+			}
+			if _, err := client.MarkTaskComplete(task.ID); err != nil {
+				return err
+			}
+			recordUndo("focus", journal.ActionModify, preImages)
+			completedTask = true
+		}
+
+		return outputFocusSummary(task.Description, completed, rounds, logged, interrupted, completedTask)
+	},
+}
+
+var focusStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the running focus session on one line",
+	Long:  "Prints e.g. \"work 2/4 12:34 Write report\", or nothing when no session is running, so it can be polled by tmux, i3blocks, waybar and similar status bars.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := timer.Open()
+		if err != nil {
+			return err
+		}
+
+		st, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !st.FocusRunning(now) {
+			if IsJSONOutput() {
+				fmt.Println("null")
+			}
+			return nil
+		}
+
+		if IsJSONOutput() {
+			data, err := json.MarshalIndent(struct {
+				*timer.Focus
+				RemainingSeconds int `json:"remaining_seconds"`
+			}{st.Focus, int(st.Focus.Remaining(now) / time.Second)}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Println(st.Focus.StatusLine(now))
+		return nil
+	},
+}
+
+func init() {
+	focusCmd.Flags().Duration("work", 25*time.Minute, "Length of a work round")
+	focusCmd.Flags().Duration("break", 5*time.Minute, "Length of the break between rounds")
+	focusCmd.Flags().Int("rounds", 4, "Number of work rounds")
+	focusCmd.Flags().Bool("complete", false, "Mark the task complete after the last round")
+	focusCmd.Flags().Bool("plain", false, "Print one line per phase instead of a live countdown")
+	focusCmd.Flags().Bool("no-bell", false, "Do not ring the terminal bell")
+
+	focusCmd.AddCommand(focusStatusCmd)
+}
+
+// focusOutput draws the countdown, as a live line on terminals and as one
+// line per phase otherwise
+type focusOutput struct {
+	interactive bool
+	bell        bool
+	label       string
+}
+
+func (o *focusOutput) phase(phase timer.Phase, round, rounds int, description string, end time.Time) {
+	if IsJSONOutput() {
+		return
+	}
+	o.label = fmt.Sprintf("%s %d/%d  %s", phase, round, rounds, description)
+	if !o.interactive {
+		fmt.Printf("%s until %s\n", o.label, end.Format("15:04"))
+	}
+}
+
+func (o *focusOutput) tick(remaining time.Duration) {
+	if !o.interactive || IsJSONOutput() {
+		return
+	}
+	remaining = remaining.Round(time.Second)
+	// \033[K clears what is left of a longer previous line
+	fmt.Printf("\r%02d:%02d  %s\033[K", int(remaining/time.Minute), int(remaining%time.Minute/time.Second), o.label)
+}
+
+func (o *focusOutput) ring() {
+	if o.interactive && !IsJSONOutput() {
+		fmt.Print("\r\033[K")
+	}
+	if o.bell {
+		fmt.Print("\a")
+	}
+}
+
+// countdown waits until end, redrawing the remaining time every second.
+// It returns true when interrupted by a signal.
+func countdown(end time.Time, signals <-chan os.Signal, out *focusOutput) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		remaining := time.Until(end)
+		if remaining <= 0 {
+			return false
+		}
+		out.tick(remaining)
+
+		select {
+		case <-signals:
+			if out.interactive && !IsJSONOutput() {
+				fmt.Println()
+			}
+			return true
+		case <-ticker.C:
+		case <-time.After(remaining):
+		}
+	}
+}
+
+func outputFocusSummary(description string, completed, rounds int, logged time.Duration, interrupted, completedTask bool) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"description":      description,
+			"rounds_completed": completed,
+			"rounds":           rounds,
+			"logged_seconds":   int(logged / time.Second),
+			"interrupted":      interrupted,
+			"task_completed":   completedTask,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Completed %d of %d round(s), logged %s on %s\n", completed, rounds, formatClock(logged), description)
+	if interrupted {
+		fmt.Println("Interrupted, the unfinished round was not logged")
+	}
+	if completedTask {
+		fmt.Println("Task marked complete")
+	}
+	return nil
+}
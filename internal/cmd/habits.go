@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/chart"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var habitsCmd = &cobra.Command{
+	Use:   "habits [series-id or description]",
+	Short: "Show streaks and a heatmap for recurring tasks",
+	Long:  "Groups the instances of recurring tasks by series and shows how many were completed, the current and longest streak of completed occurrences, and a heatmap with one column per week. In the heatmap █ is done, · is missed and blank means not scheduled.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+
+		now := time.Now()
+		start, end, err := dateutil.ParseRange(from, to, now)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dateutil.Range(start, end))
+		if err != nil {
+			return err
+		}
+
+		habits := report.Habits(tasks, dateutil.Format(now))
+		if len(args) == 1 {
+			query := strings.ToLower(args[0])
+			var matched []report.Habit
+			for _, h := range habits {
+				if h.RecurringID == args[0] || strings.Contains(strings.ToLower(h.Description), query) {
+					matched = append(matched, h)
+				}
+			}
+			habits = matched
+		}
+
+		return outputHabits(habits, start, end)
+	},
+}
+
+func init() {
+	habitsCmd.Flags().String("from", "-83d", "Start date (YYYY-MM-DD, today, -12w, ...)")
+	habitsCmd.Flags().String("to", "today", "End date (YYYY-MM-DD, today, ...)")
+}
+
+func outputHabits(habits []report.Habit, from, to time.Time) error {
+	if IsJSONOutput() {
+		if habits == nil {
+			habits = []report.Habit{}
+		}
+		data, err := json.MarshalIndent(habits, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(habits) == 0 {
+		fmt.Println("No recurring tasks found")
+		return nil
+	}
+
+	for i, h := range habits {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%s (%s)\n", h.Description, h.RecurringID)
+		fmt.Printf("  Completed %d of %d (%.0f%%), streak %d, longest %d\n",
+			h.Completed, h.Scheduled, h.Rate*100, h.CurrentStreak, h.LongestStreak)
+
+		days := make(map[string]bool, len(h.Days))
+		for _, d := range h.Days {
+			days[d.Date] = d.Done
+		}
+		lines := chart.Heatmap(from, to, func(day time.Time) int {
+			done, scheduled := days[dateutil.Format(day)]
+			switch {
+			case !scheduled:
+				return chart.LevelNone
+			case done:
+				return chart.LevelMax
+			default:
+				return 0
+			}
+		})
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent task changes that can be undone",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		entries, err := journal.List()
+		if err != nil {
+			return err
+		}
+
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		return outputHistory(entries)
+	},
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [entry-id]",
+	Short: "Revert a recorded task change",
+	Long:  "Reverts the most recent change recorded in the local journal, or the entry with the given ID. Deleted tasks are recreated with new IDs.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := 0
+		if len(args) == 1 {
+			var err error
+			id, err = strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid entry ID: %s", args[0])
+			}
+		}
+
+		entry, err := journal.Find(id)
+		if err != nil {
+			return err
+		}
+		if entry.Undone {
+			return fmt.Errorf("journal entry %d has already been undone", entry.ID)
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := revertEntry(client, entry)
+		if err != nil {
+			return err
+		}
+
+		if err := journal.MarkUndone(entry.ID); err != nil {
+			return err
+		}
+
+		if IsJSONOutput() {
+			return outputTasks(tasks)
+		}
+
+		fmt.Printf("Undid #%d (%s), %d task(s) restored\n", entry.ID, entry.Command, len(tasks))
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().Int("limit", 20, "Number of entries to show (0 for all)")
+}
+
+// revertEntry puts every task of a journal entry back into its recorded state
+func revertEntry(client *api.Client, entry *journal.Entry) ([]models.Task, error) {
+	var restored []models.Task
+
+	for i := range entry.Tasks {
+		pre := &entry.Tasks[i]
+
+		var task *models.Task
+		var err error
+		switch entry.Action {
+		case journal.ActionDelete:
+			task, err = client.CreateTask(journal.RecreateRequest(pre))
+			if err == nil && pre.Complete {
+				task, err = client.MarkTaskComplete(task.ID)
+			}
+		default:
+			task, err = client.UpdateTask(pre.ID, journal.RestoreRequest(pre))
+		}
+		if err != nil {
+			return restored, fmt.Errorf("failed to restore task %s: %w", pre.ID, err)
+		}
+
+		restored = append(restored, *task)
+	}
+
+	return restored, nil
+}
+
+// snapshotTasks fetches the current state of tasks before they are modified
+func snapshotTasks(client *api.Client, taskIDs ...string) ([]models.Task, error) {
+	tasks := make([]models.Task, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		task, err := client.GetTask(id)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+// recordUndo stores pre-images in the journal. The change itself already
+// happened, so a journal failure only produces a warning.
+func recordUndo(command string, action journal.Action, tasks []models.Task) {
+	if err := writeUndo(command, action, tasks); err != nil {
+		warnUndo(err)
+	}
+}
+
+// writeUndo stores pre-images in the journal and returns any failure, for
+// callers that cannot print the warning right away
+func writeUndo(command string, action journal.Action, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	_, err := journal.Record(command, action, tasks)
+	return err
+}
+
+func warnUndo(err error) {
+	fmt.Fprintf(os.Stderr, "Warning: failed to record undo journal: %v\n", err)
+}
+
+func outputHistory(entries []journal.Entry) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No history found")
+		return nil
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+
+		status := ""
+		if entry.Undone {
+			status = " (undone)"
+		}
+
+		fmt.Printf("#%d %s  %s, %d task(s)%s\n", entry.ID, entry.Time.Format("2006-01-02 15:04"), entry.Command, len(entry.Tasks), status)
+		for _, task := range entry.Tasks {
+			fmt.Printf("    %s (%s)\n", task.Description, task.ID)
+		}
+	}
+	return nil
+}
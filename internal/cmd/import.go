@@ -0,0 +1,600 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/ical"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/ledger"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/org"
+	"github.com/goldie/ellie-cli/internal/taskcsv"
+	"github.com/goldie/ellie-cli/internal/taskwarrior"
+	"github.com/goldie/ellie-cli/internal/todotxt"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import tasks from other formats",
+}
+
+var importICSCmd = &cobra.Command{
+	Use:   "ics <file>",
+	Short: "Import to-dos and events from an iCalendar file",
+	Long:  "Creates a task for every VTODO and VEVENT in the file. Items are remembered by UID so re-importing the same file does not create duplicates.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		list, _ := cmd.Flags().GetString("list")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open calendar: %w", err)
+		}
+		defer f.Close()
+
+		cal, err := ical.Decode(f)
+		if err != nil {
+			return err
+		}
+
+		items, err := ical.Items(cal, loc)
+		if err != nil {
+			return err
+		}
+
+		book, err := ledger.Open("ics")
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		res := newResolver(client)
+
+		var listID string
+		if list != "" {
+			if listID, err = res.listID(list); err != nil {
+				return err
+			}
+		}
+
+		var planned []importItem
+		seen := make(map[string]bool)
+		for i := range items {
+			item := &items[i]
+			key := item.Key()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			req, warnings := icsCreateRequest(item, res, listID)
+			entry := importItem{Key: key, Request: req, Complete: item.Completed, Warnings: warnings}
+			if record, ok := book.Get(key); ok {
+				entry.ExistingID = record.TaskID
+			}
+			planned = append(planned, entry)
+		}
+
+		return runImport(client, book, planned, dryRun)
+	},
+}
+
+var importTodoTxtCmd = &cobra.Command{
+	Use:   "todotxt <file>",
+	Short: "Import tasks from a todo.txt file",
+	Long:  "Creates a task for every line. (A)-(D) map to priorities 4-1, +projects to lists, @contexts to labels, t: to the date and due: to the due date. Lines are remembered so re-importing the file does not create duplicates.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open todo.txt: %w", err)
+		}
+		defer f.Close()
+
+		items, _, err := todotxt.Read(f)
+		if err != nil {
+			return err
+		}
+
+		book, err := ledger.Open("todotxt")
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		var planned []importItem
+		seen := make(map[string]bool)
+		for _, item := range items {
+			key := item.Key()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			req, warnings := todotxt.ToRequest(item, names)
+			entry := importItem{Key: key, Request: req, Complete: item.Complete, Warnings: warnings}
+			if record, ok := book.Get(key); ok {
+				entry.ExistingID = record.TaskID
+			}
+			planned = append(planned, entry)
+		}
+
+		return runImport(client, book, planned, dryRun)
+	},
+}
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv <file>",
+	Short: "Import tasks from a CSV file",
+	Long: `Creates a task for every row of a CSV file. Columns are matched to task fields by
+header name; override the mapping with --map field=Header or a YAML/JSON --mapping file.
+
+Fields: ` + strings.Join(taskcsv.Fields, ", ") + `
+
+Lists and labels may be given by name or ID. All rows are validated before anything is created.
+Rows whose id column names an existing task, as in files written by 'ellie export csv', are skipped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipInvalid, _ := cmd.Flags().GetBool("skip-invalid")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		mappingFlags, _ := cmd.Flags().GetStringArray("map")
+		dateFormat, _ := cmd.Flags().GetString("date-format")
+
+		mapping := taskcsv.DefaultMapping()
+		if mappingFile != "" {
+			fileMapping, err := taskcsv.LoadMapping(mappingFile)
+			if err != nil {
+				return err
+			}
+			mapping = mapping.Merge(fileMapping)
+		}
+		flagMapping, err := taskcsv.ParseMappingFlags(mappingFlags)
+		if err != nil {
+			return err
+		}
+		mapping = mapping.Merge(flagMapping)
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open CSV file: %w", err)
+		}
+		defer f.Close()
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		rows, rowErrors, err := taskcsv.Read(f, taskcsv.Options{
+			Mapping:    mapping,
+			Names:      names,
+			DateLayout: dateFormat,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, rowErr := range rowErrors {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", rowErr)
+		}
+		if len(rowErrors) > 0 && !skipInvalid && !dryRun {
+			return fmt.Errorf("%d invalid row(s), fix them or pass --skip-invalid", len(rowErrors))
+		}
+
+		book, err := ledger.Open("csv")
+		if err != nil {
+			return err
+		}
+
+		var planned []importItem
+		for _, row := range rows {
+			key := csvKey(row.Request)
+			entry := importItem{Key: key, Request: row.Request, Complete: row.Complete}
+			if record, ok := book.Get(key); ok {
+				entry.ExistingID = record.TaskID
+			} else if row.ID != "" {
+				if _, err := client.GetTask(row.ID); err == nil {
+					entry.ExistingID = row.ID
+				}
+			}
+			planned = append(planned, entry)
+		}
+
+		return runImport(client, book, planned, dryRun)
+	},
+}
+
+var importOrgCmd = &cobra.Command{
+	Use:   "org <file>",
+	Short: "Create or update tasks from an org-mode file",
+	Long:  "Reads TODO/DONE headings. Headings with an :ELLIE_ID: property update that task where it differs; other headings create new tasks and are remembered so re-importing does not create duplicates.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open org file: %w", err)
+		}
+		headings, err := org.Parse(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		book, err := ledger.Open("org")
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		var creates []importItem
+		var updates []orgUpdate
+		unchanged := 0
+		for i := range headings {
+			h := &headings[i]
+
+			if id := h.ID(); id != "" {
+				task, err := client.GetTask(id)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: line %d: task %s: %v\n", h.Line, id, err)
+					continue
+				}
+				req, warnings := h.UpdateRequest(task, names, loc)
+				printWarnings(h.Title, warnings)
+				if req == nil {
+					unchanged++
+					continue
+				}
+				updates = append(updates, orgUpdate{Task: task, Request: req})
+				continue
+			}
+
+			key := h.Title
+			if h.Scheduled != nil {
+				key += "|" + h.Scheduled.Date
+			}
+			req, warnings := h.CreateRequest(names)
+			entry := importItem{Key: key, Request: req, Complete: h.Done, Warnings: warnings}
+			if record, ok := book.Get(key); ok {
+				entry.ExistingID = record.TaskID
+			}
+			creates = append(creates, entry)
+		}
+
+		if dryRun && !IsJSONOutput() {
+			for _, update := range updates {
+				fmt.Printf("~ %s\n", update.Task.Description)
+			}
+			if len(updates) > 0 || unchanged > 0 {
+				fmt.Printf("%d task(s) to update, %d unchanged\n\n", len(updates), unchanged)
+			}
+		}
+		if dryRun {
+			return outputImportPlan(creates)
+		}
+
+		var preImages []models.Task
+		var updateErr error
+		for _, update := range updates {
+			if _, err := client.UpdateTask(update.Task.ID, update.Request); err != nil {
+				updateErr = fmt.Errorf("failed to update task %s: %w", update.Task.ID, err)
+				break
+			}
+			preImages = append(preImages, *update.Task)
+		}
+		recordUndo("import org", journal.ActionModify, preImages)
+		if updateErr != nil {
+			return updateErr
+		}
+		if !IsJSONOutput() && len(updates) > 0 {
+			fmt.Printf("Updated %d task(s)\n", len(preImages))
+		}
+
+		return runImport(client, book, creates, false)
+	},
+}
+
+var importTaskwarriorCmd = &cobra.Command{
+	Use:   "taskwarrior <file>",
+	Short: "Import tasks from 'task export' JSON",
+	Long:  "Creates a task for every pending, waiting or completed Taskwarrior task. H/M/L map to priorities 3-1, projects to lists, tags to labels, scheduled to the date and start time, due to the due date, and annotations are appended to the description. Deleted tasks and recurrence templates are skipped. Tasks are remembered by UUID so re-importing does not create duplicates. Pass - to read from stdin.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		in := os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open Taskwarrior export: %w", err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		tasks, err := taskwarrior.Read(in)
+		if err != nil {
+			return err
+		}
+
+		book, err := ledger.Open("taskwarrior")
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		var planned []importItem
+		seen := make(map[string]bool)
+		for i := range tasks {
+			task := &tasks[i]
+			if !task.Importable() || task.UUID == "" || seen[task.UUID] {
+				continue
+			}
+			seen[task.UUID] = true
+
+			req, warnings := task.CreateRequest(names, loc)
+			entry := importItem{Key: task.UUID, Request: req, Complete: task.Status == taskwarrior.StatusCompleted, Warnings: warnings}
+			if record, ok := book.Get(task.UUID); ok {
+				entry.ExistingID = record.TaskID
+			}
+			planned = append(planned, entry)
+		}
+
+		return runImport(client, book, planned, dryRun)
+	},
+}
+
+func init() {
+	importICSCmd.Flags().Bool("dry-run", false, "Show what would be imported without creating tasks")
+	importICSCmd.Flags().String("timezone", "", "Timezone to import times into (default: local)")
+	importICSCmd.Flags().String("list", "", "List name or ID to put imported tasks in")
+	importCmd.AddCommand(importICSCmd)
+
+	importTodoTxtCmd.Flags().Bool("dry-run", false, "Show what would be imported without creating tasks")
+	importCmd.AddCommand(importTodoTxtCmd)
+
+	importCSVCmd.Flags().Bool("dry-run", false, "Validate and show what would be imported without creating tasks")
+	importCSVCmd.Flags().Bool("skip-invalid", false, "Import the valid rows even if some rows are invalid")
+	importCSVCmd.Flags().String("mapping", "", "YAML or JSON file mapping task fields to CSV headers")
+	importCSVCmd.Flags().StringArray("map", nil, "Map a task field to a CSV header, e.g. description=\"Task Name\" (repeatable)")
+	importCSVCmd.Flags().String("date-format", "2006-01-02", "Go time layout of date columns, e.g. 01/02/2006")
+	importCmd.AddCommand(importCSVCmd)
+
+	importOrgCmd.Flags().Bool("dry-run", false, "Show what would change without modifying tasks")
+	importOrgCmd.Flags().String("timezone", "", "Timezone of the file's timestamps (default: local)")
+	importCmd.AddCommand(importOrgCmd)
+
+	importTaskwarriorCmd.Flags().Bool("dry-run", false, "Show what would be imported without creating tasks")
+	importTaskwarriorCmd.Flags().String("timezone", "", "Timezone to import times into (default: local)")
+	importCmd.AddCommand(importTaskwarriorCmd)
+}
+
+// importItem is a task an importer plans to create
+type importItem struct {
+	Key        string                    `json:"key"`
+	Request    *models.CreateTaskRequest `json:"request"`
+	Complete   bool                      `json:"complete"`
+	ExistingID string                    `json:"existing_id,omitempty"`
+	Warnings   []string                  `json:"warnings,omitempty"`
+}
+
+// orgUpdate is a change to an existing task read from an org heading
+type orgUpdate struct {
+	Task    *models.Task
+	Request *models.UpdateTaskRequest
+}
+
+func printWarnings(subject string, warnings []string) {
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", subject, warning)
+	}
+}
+
+// icsCreateRequest maps a calendar item onto a task. The first category
+// that matches a label name becomes the task's label; the others are
+// reported as warnings.
+func icsCreateRequest(item *ical.Item, res *resolver, listID string) (*models.CreateTaskRequest, []string) {
+	req := &models.CreateTaskRequest{Description: item.Summary}
+	var warnings []string
+
+	if item.Date != "" {
+		req.Date = &item.Date
+	}
+	if item.Start != "" {
+		req.Start = &item.Start
+	}
+	if item.Duration > 0 {
+		seconds := int(item.Duration / time.Second)
+		req.EstimatedTime = &seconds
+	}
+	if item.Priority > 0 {
+		req.Priority = &item.Priority
+	}
+	if listID != "" {
+		req.ListID = &listID
+	}
+	for _, category := range item.Categories {
+		id, err := res.labelID(category)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("category %q ignored: %v", category, err))
+		case req.Label == nil:
+			req.Label = &id
+		default:
+			warnings = append(warnings, fmt.Sprintf("category %q ignored, tasks have a single label", category))
+		}
+	}
+	return req, warnings
+}
+
+// runImport creates the planned tasks, skipping those already recorded in
+// the ledger, or prints the plan when dryRun is set
+func runImport(client *api.Client, book *ledger.Ledger, planned []importItem, dryRun bool) error {
+	if dryRun {
+		return outputImportPlan(planned)
+	}
+
+	var created []models.Task
+	skipped := 0
+	var importErr error
+
+	for _, item := range planned {
+		if item.ExistingID != "" {
+			skipped++
+			continue
+		}
+
+		task, err := client.CreateTask(item.Request)
+		if err != nil {
+			importErr = fmt.Errorf("failed to create task %q: %w", item.Request.Description, err)
+			break
+		}
+		if item.Complete {
+			if task, err = client.MarkTaskComplete(task.ID); err != nil {
+				importErr = fmt.Errorf("failed to complete task %q: %w", item.Request.Description, err)
+				break
+			}
+		}
+
+		book.Put(item.Key, task.ID)
+		created = append(created, *task)
+		printWarnings(item.Request.Description, item.Warnings)
+	}
+
+	if err := book.Save(); err != nil {
+		return err
+	}
+	if importErr != nil {
+		return importErr
+	}
+
+	if IsJSONOutput() {
+		return outputTasks(created)
+	}
+	fmt.Printf("Imported %d task(s), skipped %d already imported\n", len(created), skipped)
+	return nil
+}
+
+func outputImportPlan(planned []importItem) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(planned, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	toCreate := 0
+	for _, item := range planned {
+		marker := "+"
+		if item.ExistingID != "" {
+			marker = "="
+		} else {
+			toCreate++
+		}
+
+		fmt.Printf("%s %s\n", marker, describeCreateRequest(item.Request, item.Complete))
+		for _, warning := range item.Warnings {
+			fmt.Printf("    ! %s\n", warning)
+		}
+	}
+
+	fmt.Printf("\n%d task(s) to import, %d already imported\n", toCreate, len(planned)-toCreate)
+	return nil
+}
+
+func describeCreateRequest(req *models.CreateTaskRequest, complete bool) string {
+	desc := req.Description
+	if req.Date != nil {
+		when := *req.Date
+		if req.Start != nil {
+			when += " " + *req.Start
+		}
+		desc += " [" + when + "]"
+	}
+	if req.EstimatedTime != nil {
+		desc += fmt.Sprintf(" (%d min)", *req.EstimatedTime/60)
+	}
+	if complete {
+		desc += " (done)"
+	}
+	return desc
+}
+
+// csvKey identifies a row across imports by what the task looks like,
+// since spreadsheets rarely carry stable IDs
+func csvKey(req *models.CreateTaskRequest) string {
+	key := req.Description
+	if req.Date != nil {
+		key += "|" + *req.Date
+	}
+	if req.Start != nil {
+		key += "|" + *req.Start
+	}
+	return key
+}
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plan a day's time",
+}
+
+var planScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Timebox unscheduled tasks into the free time of a day",
+	Long: `Places the day's tasks without a start time, and braindump tasks with --braindump, into the
+gaps between tasks that already have one. Higher priorities and earlier due dates go first,
+each at the earliest gap that fits its estimate. --buffer keeps time free after every task and
+--break blocks out time such as lunch. For today nothing is placed before the current time.
+
+The timeline is shown before any start time is changed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		workHours, _ := cmd.Flags().GetString("work-hours")
+		breaks, _ := cmd.Flags().GetStringSlice("break")
+		buffer, _ := cmd.Flags().GetDuration("buffer")
+		defaultEstimate, _ := cmd.Flags().GetDuration("default-estimate")
+		braindump, _ := cmd.Flags().GetBool("braindump")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		opts := planner.Options{
+			Buffer:          int(buffer / time.Minute),
+			DefaultEstimate: int(defaultEstimate / time.Minute),
+			Step:            5,
+		}
+		var err error
+		if opts.Hours, err = planner.ParseWindow(workHours); err != nil {
+			return err
+		}
+		for _, b := range breaks {
+			w, err := planner.ParseWindow(b)
+			if err != nil {
+				return err
+			}
+			opts.Breaks = append(opts.Breaks, w)
+		}
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+		now := time.Now().In(loc)
+
+		day, err := dateutil.Parse(date, now)
+		if err != nil {
+			return err
+		}
+		dayStr := dateutil.Format(day)
+		if day.Before(dateutil.Truncate(now)) {
+			return fmt.Errorf("%s is in the past", dayStr)
+		}
+		if dayStr == dateutil.Format(now) {
+			opts.Earliest = planner.ClockOf(now)
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, []string{dayStr})
+		if err != nil {
+			return err
+		}
+		fixed, open := planner.Split(tasks, dayStr, loc)
+
+		if braindump {
+			unscheduled, err := client.GetBraindump()
+			if err != nil {
+				return fmt.Errorf("failed to fetch braindump: %w", err)
+			}
+			for _, task := range unscheduled {
+				if !task.Complete {
+					open = append(open, task)
+				}
+			}
+		}
+
+		var candidates []models.Task
+		var recurring []planner.Unplaced
+		for _, task := range open {
+			if task.Recurring || task.RecurringID != nil {
+				recurring = append(recurring, planner.Unplaced{Task: task, Reason: "recurring instance"})
+				continue
+			}
+			candidates = append(candidates, task)
+		}
+
+		plan := planner.Schedule(fixed, candidates, opts)
+		plan.Unplaced = append(plan.Unplaced, recurring...)
+
+		if err := outputPlan(dayStr, opts.Hours, plan); err != nil {
+			return err
+		}
+		scheduled := plan.Scheduled()
+		if len(scheduled) == 0 || dryRun {
+			return nil
+		}
+
+		if !yes {
+			ok, err := confirm(fmt.Sprintf("Schedule %d task(s)?", len(scheduled)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+
+		var preImages []models.Task
+		var scheduleErr error
+		for _, block := range scheduled {
+			start := block.Start.String()
+			req := &models.UpdateTaskRequest{Start: &start}
+			if block.Task.GetDateString() != dayStr {
+				req.Date = &dayStr
+			}
+			if _, err := client.UpdateTask(block.Task.ID, req); err != nil {
+				scheduleErr = fmt.Errorf("failed to schedule task %q: %w", block.Task.Description, err)
+				break
+			}
+			preImages = append(preImages, *block.Task)
+		}
+		recordUndo("plan schedule", journal.ActionModify, preImages)
+
+		if scheduleErr != nil {
+			return scheduleErr
+		}
+		if !IsJSONOutput() {
+			fmt.Printf("Scheduled %d task(s)\n", len(preImages))
+		}
+		return nil
+	},
+}
+
+var planCapacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: "Compare the estimated work per day with the time available",
+	Long:  "Sums the estimates of each day's tasks and compares them with the working time configured with 'ellie config set-capacity'. Overcommitted days and open tasks without an estimate are flagged.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		start, end, err := dateutil.ParseRange(from, to, time.Now().In(loc))
+		if err != nil {
+			return err
+		}
+		dates := dateutil.Range(start, end)
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dates)
+		if err != nil {
+			return err
+		}
+
+		return outputDayLoads(planner.Load(dates, tasks, loadCapacity(), loc))
+	},
+}
+
+var planConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Report overlapping and tightly packed timed tasks",
+	Long: `Checks the tasks with a start time on a date for overlaps, for tasks starting less than
+--buffer after the previous one ends and for tasks ending after working hours. Tasks last
+for their estimate; completed tasks are ignored.
+
+Each conflict is printed on one line, or as a JSON array with --json. With --exit-code the
+command exits with status 1 when conflicts are found, for use in cron jobs and scripts.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		workHours, _ := cmd.Flags().GetString("work-hours")
+		buffer, _ := cmd.Flags().GetDuration("buffer")
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+
+		hours, err := planner.ParseWindow(workHours)
+		if err != nil {
+			return err
+		}
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		day, err := dateutil.Parse(date, time.Now().In(loc))
+		if err != nil {
+			return err
+		}
+		dayStr := dateutil.Format(day)
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, []string{dayStr})
+		if err != nil {
+			return err
+		}
+		fixed, _ := planner.Split(tasks, dayStr, loc)
+
+		conflicts := planner.Conflicts(fixed, hours, int(buffer/time.Minute))
+		if err := outputConflicts(dayStr, conflicts); err != nil {
+			return err
+		}
+		if exitCode && len(conflicts) > 0 {
+			return fmt.Errorf("%d conflict(s) on %s", len(conflicts), dayStr)
+		}
+		return nil
+	},
+}
+
+func init() {
+	planScheduleCmd.Flags().String("date", "today", "Date to plan (YYYY-MM-DD, today, tomorrow, ...)")
+	planScheduleCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	planScheduleCmd.Flags().String("work-hours", "09:00-17:00", "Working hours to fill")
+	planScheduleCmd.Flags().StringSlice("break", nil, "Time to keep free, e.g. 12:00-13:00 (repeatable)")
+	planScheduleCmd.Flags().Duration("buffer", 5*time.Minute, "Free time to keep after every task")
+	planScheduleCmd.Flags().Duration("default-estimate", 0, "Length for tasks without an estimate (default: leave them out)")
+	planScheduleCmd.Flags().Bool("braindump", false, "Also schedule tasks from the braindump")
+	planScheduleCmd.Flags().Bool("dry-run", false, "Only show the timeline")
+	planScheduleCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+
+	planCapacityCmd.Flags().String("from", "today", "Start date (YYYY-MM-DD, today, -7d, ...)")
+	planCapacityCmd.Flags().String("to", "+6d", "End date (YYYY-MM-DD, today, +7d, ...)")
+	planCapacityCmd.Flags().String("timezone", "", "Timezone (e.g., America/New_York)")
+
+	planConflictsCmd.Flags().String("date", "today", "Date to check (YYYY-MM-DD, today, tomorrow, ...)")
+	planConflictsCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	planConflictsCmd.Flags().String("work-hours", "09:00-17:00", "Working hours tasks should end within")
+	planConflictsCmd.Flags().Duration("buffer", 5*time.Minute, "Minimum free time between tasks")
+	planConflictsCmd.Flags().Bool("exit-code", false, "Exit with status 1 when conflicts are found")
+
+	planCmd.AddCommand(planScheduleCmd)
+	planCmd.AddCommand(planCapacityCmd)
+	planCmd.AddCommand(planConflictsCmd)
+}
+
+func outputPlan(date string, hours planner.Window, plan *planner.Plan) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(struct {
+			Date  string         `json:"date"`
+			Hours planner.Window `json:"work_hours"`
+			*planner.Plan
+		}{date, hours, plan}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Schedule for %s (%s)\n\n", date, hours)
+	if len(plan.Blocks) == 0 {
+		fmt.Println("  Nothing to schedule")
+	}
+	for _, b := range plan.Blocks {
+		marker := " "
+		if b.Kind == planner.KindScheduled {
+			marker = "+"
+		}
+		what := "break"
+		if b.Task != nil {
+			what = b.Task.Description
+			if b.Task.Complete {
+				what += " (done)"
+			}
+		}
+		fmt.Printf("  %s-%s %s %s", b.Start, b.End, marker, what)
+		if b.End > b.Start {
+			fmt.Printf(" (%s)", dateutil.FormatMinutes(int(b.End-b.Start)))
+		}
+		fmt.Println()
+	}
+
+	if len(plan.Unplaced) > 0 {
+		fmt.Println("\nNot scheduled:")
+		for _, u := range plan.Unplaced {
+			fmt.Printf("  %s (%s): %s\n", u.Task.Description, u.Task.ID, u.Reason)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func outputDayLoads(loads []planner.DayLoad) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(loads, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%-10s  %-3s  %7s  %8s  %5s\n", "Date", "Day", "Planned", "Capacity", "Load")
+	var over, unestimated int
+	for i := range loads {
+		d := &loads[i]
+		load := "-"
+		if p := d.Percent(); p >= 0 {
+			load = fmt.Sprintf("%d%%", p)
+		}
+		fmt.Printf("%-10s  %-3s  %7s  %8s  %5s", d.Date, d.Weekday, dateutil.FormatMinutes(d.Planned), dateutil.FormatMinutes(d.Capacity), load)
+		if d.Overcommitted {
+			fmt.Printf("  over by %s", dateutil.FormatMinutes(d.Over))
+			over++
+		}
+		if n := len(d.Unestimated); n > 0 {
+			fmt.Printf("  %d without estimate", n)
+			unestimated += n
+		}
+		fmt.Println()
+	}
+
+	if unestimated > 0 {
+		fmt.Println("\nWithout estimate:")
+		for _, d := range loads {
+			for _, task := range d.Unestimated {
+				fmt.Printf("  %s  %s (%s)\n", d.Date, task.Description, task.ID)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d of %d day(s) overcommitted\n", over, len(loads))
+	return nil
+}
+
+func outputConflicts(date string, conflicts []planner.Conflict) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(conflicts, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Printf("No conflicts on %s\n", date)
+		return nil
+	}
+
+	for _, c := range conflicts {
+		names := make([]string, len(c.Tasks))
+		for i, task := range c.Tasks {
+			names[i] = fmt.Sprintf("%s (%s)", task.Description, task.ID)
+		}
+		fmt.Printf("%s %s-%s %-11s %s\n", date, c.Start, c.End, c.Kind, strings.Join(names, " / "))
+	}
+	return nil
+}
+
+// capacityWarning returns a warning if adding minutes of work to date
+// takes it over capacity. The check is advisory, so a failed lookup
+// yields no warning.
+func capacityWarning(client *api.Client, date string, minutes int) string {
+	tasks, err := fetchAgenda(client, []string{date})
+	if err != nil {
+		return ""
+	}
+
+	loads := planner.Load([]string{date}, tasks, loadCapacity(), time.Local)
+	if len(loads) == 0 {
+		return ""
+	}
+	load := loads[0]
+	wasOver := load.Overcommitted
+	load.Add(minutes)
+	if !load.Overcommitted || wasOver && minutes == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s is planned for %s with %s of capacity", date, dateutil.FormatMinutes(load.Planned), dateutil.FormatMinutes(load.Capacity))
+}
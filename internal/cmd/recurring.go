@@ -0,0 +1,486 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/rrule"
+	"github.com/spf13/cobra"
+)
+
+var recurringCmd = &cobra.Command{
+	Use:     "recurring",
+	Aliases: []string{"recur"},
+	Short:   "Manage recurring tasks",
+	Long:    "Recurring tasks are series defined by an RRULE such as \"FREQ=WEEKLY;BYDAY=MO,WE\". The shortcuts daily, weekdays, weekly, biweekly, monthly and yearly are accepted wherever a rule is expected.",
+}
+
+var listRecurringCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recurring task series",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		series, err := client.GetRecurringTasks()
+		if err != nil {
+			return err
+		}
+
+		return outputRecurringTasks(series)
+	},
+}
+
+var upcomingRecurringCmd = &cobra.Command{
+	Use:   "upcoming [series-id]",
+	Short: "Show upcoming occurrences of one or all series",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		start, end, err := dateutil.ParseRange(from, to, time.Now())
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		var series []models.RecurringTask
+		if len(args) == 1 {
+			s, err := client.GetRecurringTask(args[0])
+			if err != nil {
+				return err
+			}
+			series = append(series, *s)
+		} else if series, err = client.GetRecurringTasks(); err != nil {
+			return err
+		}
+
+		var upcoming []occurrence
+		for i := range series {
+			dates, err := occurrences(&series[i], start, end, 0)
+			if err != nil {
+				printWarnings(series[i].Description, []string{err.Error()})
+				continue
+			}
+			for _, date := range dates {
+				upcoming = append(upcoming, occurrence{Date: date, Series: &series[i]})
+			}
+		}
+		sort.SliceStable(upcoming, func(i, j int) bool {
+			a, b := upcoming[i], upcoming[j]
+			if a.Date != b.Date {
+				return a.Date < b.Date
+			}
+			return stringValue(a.Series.Start) < stringValue(b.Series.Start)
+		})
+		if limit > 0 && len(upcoming) > limit {
+			upcoming = upcoming[:limit]
+		}
+
+		return outputOccurrences(upcoming)
+	},
+}
+
+var createRecurringCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a recurring task series",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		desc, _ := cmd.Flags().GetString("desc")
+		ruleText, _ := cmd.Flags().GetString("rule")
+		date, _ := cmd.Flags().GetString("date")
+		start, _ := cmd.Flags().GetString("start")
+		estimatedTime, _ := cmd.Flags().GetInt("estimated-time")
+		listID, _ := cmd.Flags().GetString("list-id")
+		label, _ := cmd.Flags().GetString("label")
+		priority, _ := cmd.Flags().GetInt("priority")
+
+		if desc == "" {
+			return fmt.Errorf("--desc flag is required")
+		}
+		if ruleText == "" {
+			return fmt.Errorf("--rule flag is required")
+		}
+
+		rule, err := rrule.Parse(ruleText)
+		if err != nil {
+			return err
+		}
+		first, err := dateutil.Parse(date, time.Now())
+		if err != nil {
+			return err
+		}
+
+		req := &models.CreateRecurringTaskRequest{
+			Description: desc,
+			Rule:        rule.String(),
+			StartDate:   dateutil.Format(first),
+		}
+		if start != "" {
+			req.Start = &start
+		}
+		if estimatedTime > 0 {
+			req.EstimatedTime = &estimatedTime
+		}
+		if listID != "" {
+			req.ListID = &listID
+		}
+		if label != "" {
+			req.Label = &label
+		}
+		if priority > 0 {
+			req.Priority = &priority
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		series, err := client.CreateRecurringTask(req)
+		if err != nil {
+			return err
+		}
+
+		return outputRecurringTask(series)
+	},
+}
+
+var skipRecurringCmd = &cobra.Command{
+	Use:   "skip <series-id> <date>",
+	Short: "Skip a single occurrence of a series",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		series, date, err := findOccurrence(client, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := client.SkipRecurringOccurrence(series.ID, date); err != nil {
+			return err
+		}
+
+		if IsJSONOutput() {
+			fmt.Printf("{\"skipped\": true, \"recurring_id\": %q, \"date\": %q}\n", series.ID, date)
+			return nil
+		}
+		fmt.Printf("Skipped %s on %s\n", series.Description, date)
+		return nil
+	},
+}
+
+var rescheduleRecurringCmd = &cobra.Command{
+	Use:   "reschedule <series-id> <date>",
+	Short: "Move a single occurrence of a series to another date or time",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		start, _ := cmd.Flags().GetString("start")
+
+		if to == "" && start == "" {
+			return fmt.Errorf("--to or --start is required")
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		series, date, err := findOccurrence(client, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		req := &models.RecurringOccurrenceRequest{RecurringID: series.ID, Date: date}
+		if to != "" {
+			newDate, err := dateutil.Parse(to, time.Now())
+			if err != nil {
+				return err
+			}
+			formatted := dateutil.Format(newDate)
+			req.NewDate = &formatted
+		}
+		if start != "" {
+			req.Start = &start
+		}
+
+		task, err := client.RescheduleRecurringOccurrence(req)
+		if err != nil {
+			return err
+		}
+
+		return outputTask(task)
+	},
+}
+
+var stopRecurringCmd = &cobra.Command{
+	Use:   "stop <series-id>",
+	Short: "End a recurring task series",
+	Long:  "Ends the series so that it has no occurrences on or after --from. Earlier occurrences are kept.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		endDate, err := dateutil.Parse(from, time.Now())
+		if err != nil {
+			return err
+		}
+		end := dateutil.Format(endDate)
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		series, err := client.GetRecurringTask(args[0])
+		if err != nil {
+			return err
+		}
+
+		if !yes {
+			ok, err := confirm(fmt.Sprintf("Stop %q (%s) from %s?", series.Description, describeRule(series.Rule), end))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("aborted")
+			}
+		}
+
+		stopped, err := client.StopRecurringTask(series.ID, end)
+		if err != nil {
+			return err
+		}
+
+		return outputRecurringTask(stopped)
+	},
+}
+
+func init() {
+	upcomingRecurringCmd.Flags().String("from", "today", "Start date (YYYY-MM-DD, today, ...)")
+	upcomingRecurringCmd.Flags().String("to", "+13d", "End date (YYYY-MM-DD, today, +14d, ...)")
+	upcomingRecurringCmd.Flags().Int("limit", 0, "Maximum number of occurrences to show (0 for all)")
+
+	createRecurringCmd.Flags().String("desc", "", "Task description (required)")
+	createRecurringCmd.Flags().String("rule", "", "Recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO or weekdays (required)")
+	createRecurringCmd.Flags().String("date", "today", "Date of the first occurrence")
+	createRecurringCmd.Flags().String("start", "", "Start time")
+	createRecurringCmd.Flags().Int("estimated-time", 0, "Estimated time in seconds")
+	createRecurringCmd.Flags().String("list-id", "", "List ID")
+	createRecurringCmd.Flags().String("label", "", "Label ID")
+	createRecurringCmd.Flags().Int("priority", 0, "Priority (1-4)")
+
+	rescheduleRecurringCmd.Flags().String("to", "", "New date of the occurrence")
+	rescheduleRecurringCmd.Flags().String("start", "", "New start time of the occurrence")
+
+	stopRecurringCmd.Flags().String("from", "tomorrow", "First date without occurrences")
+	stopRecurringCmd.Flags().BoolP("yes", "y", false, "Stop without asking for confirmation")
+
+	recurringCmd.AddCommand(listRecurringCmd)
+	recurringCmd.AddCommand(upcomingRecurringCmd)
+	recurringCmd.AddCommand(createRecurringCmd)
+	recurringCmd.AddCommand(skipRecurringCmd)
+	recurringCmd.AddCommand(rescheduleRecurringCmd)
+	recurringCmd.AddCommand(stopRecurringCmd)
+}
+
+// occurrence is a single date of a recurring series
+type occurrence struct {
+	Date   string
+	Series *models.RecurringTask
+}
+
+// occurrences expands a series between from and to, leaving out skipped
+// dates and dates on or after the series' end
+func occurrences(series *models.RecurringTask, from, to time.Time, limit int) ([]string, error) {
+	rule, err := rrule.Parse(series.Rule)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(dateutil.Layout, series.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date %q", series.StartDate)
+	}
+	if series.EndDate != nil && *series.EndDate != "" {
+		end, err := time.Parse(dateutil.Layout, *series.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date %q", *series.EndDate)
+		}
+		if last := end.AddDate(0, 0, -1); last.Before(dateutil.Truncate(to)) {
+			to = last
+		}
+	}
+
+	skipped := make(map[string]bool, len(series.SkippedDates))
+	for _, d := range series.SkippedDates {
+		skipped[d] = true
+	}
+
+	var dates []string
+	for _, d := range rule.Between(start, from, to, 0) {
+		date := dateutil.Format(d)
+		if skipped[date] {
+			continue
+		}
+		dates = append(dates, date)
+		if limit > 0 && len(dates) >= limit {
+			break
+		}
+	}
+	return dates, nil
+}
+
+// findOccurrence loads a series and checks that it has an occurrence on
+// the given date
+func findOccurrence(client *api.Client, seriesID, dateArg string) (*models.RecurringTask, string, error) {
+	d, err := dateutil.Parse(dateArg, time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	date := dateutil.Format(d)
+
+	series, err := client.GetRecurringTask(seriesID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dates, err := occurrences(series, d, d, 1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(dates) == 0 {
+		return nil, "", fmt.Errorf("%q has no occurrence on %s", series.Description, date)
+	}
+	return series, date, nil
+}
+
+// describeRule returns a human description of a rule, or the rule itself
+// when it cannot be parsed
+func describeRule(rule string) string {
+	if r, err := rrule.Parse(rule); err == nil {
+		return r.Describe()
+	}
+	return rule
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func outputRecurringTask(series *models.RecurringTask) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(series, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printRecurringTask(series)
+	return nil
+}
+
+func outputRecurringTasks(series []models.RecurringTask) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(series, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(series) == 0 {
+		fmt.Println("No recurring tasks found")
+		return nil
+	}
+
+	for i := range series {
+		if i > 0 {
+			fmt.Println()
+		}
+		printRecurringTask(&series[i])
+	}
+	return nil
+}
+
+func printRecurringTask(series *models.RecurringTask) {
+	fmt.Printf("%s\n", series.Description)
+	fmt.Printf("    ID: %s\n", series.ID)
+	fmt.Printf("    Repeats: %s (%s)\n", describeRule(series.Rule), series.Rule)
+	fmt.Printf("    Starts: %s\n", series.StartDate)
+	if series.EndDate != nil && *series.EndDate != "" {
+		fmt.Printf("    Ends: %s\n", *series.EndDate)
+	}
+	if series.Start != nil && *series.Start != "" {
+		fmt.Printf("    Start: %s\n", *series.Start)
+	}
+
+	today := dateutil.Truncate(time.Now())
+	if next, err := occurrences(series, today, today.AddDate(1, 0, 0), 1); err == nil && len(next) > 0 {
+		fmt.Printf("    Next: %s\n", next[0])
+	}
+}
+
+func outputOccurrences(upcoming []occurrence) error {
+	if IsJSONOutput() {
+		type item struct {
+			Date        string  `json:"date"`
+			RecurringID string  `json:"recurring_id"`
+			Description string  `json:"description"`
+			Start       *string `json:"start,omitempty"`
+		}
+		items := make([]item, 0, len(upcoming))
+		for _, o := range upcoming {
+			items = append(items, item{Date: o.Date, RecurringID: o.Series.ID, Description: o.Series.Description, Start: o.Series.Start})
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(upcoming) == 0 {
+		fmt.Println("No upcoming occurrences")
+		return nil
+	}
+
+	for _, o := range upcoming {
+		weekday := ""
+		if d, err := time.Parse(dateutil.Layout, o.Date); err == nil {
+			weekday = d.Format("Mon")
+		}
+		start := stringValue(o.Series.Start)
+		if start == "" {
+			start = "     "
+		}
+		fmt.Printf("%s %s  %s  %s (%s)\n", o.Date, weekday, start, o.Series.Description, o.Series.ID)
+	}
+	return nil
+}
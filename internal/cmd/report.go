@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports over a date range",
+}
+
+var reportEstimatesCmd = &cobra.Command{
+	Use:   "estimates",
+	Short: "Compare estimated against actual time of completed tasks",
+	Long:  "Compares the estimate of every completed task with its tracked time. A ratio above 1 means tasks took longer than estimated; the error is the average miss relative to the estimate.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		worst, _ := cmd.Flags().GetInt("worst")
+
+		start, end, err := dateutil.ParseRange(from, to, time.Now())
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dateutil.Range(start, end))
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		r := report.Estimates(dateutil.Format(start), dateutil.Format(end), tasks, names, worst)
+		return outputEstimateReport(r)
+	},
+}
+
+var reportTimesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Sum tracked time per label, list or day",
+	Long:  "Sums the actual time of tasks over a date range. Completed tasks without tracked time count with their estimate and are marked with *.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		by, _ := cmd.Flags().GetString("by")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		grouping, err := report.ParseGrouping(by)
+		if err != nil {
+			return err
+		}
+		if IsJSONOutput() {
+			format = "json"
+		}
+		switch format {
+		case "table", "csv", "json":
+		default:
+			return fmt.Errorf("invalid format %q, use table, csv or json", format)
+		}
+
+		start, end, err := dateutil.ParseRange(from, to, time.Now())
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dateutil.Range(start, end))
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		ts := report.NewTimesheet(dateutil.Format(start), dateutil.Format(end), grouping, tasks, names)
+
+		return writeOutput(output, func(w io.Writer) error {
+			switch format {
+			case "csv":
+				return ts.WriteCSV(w)
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(ts)
+			}
+			return writeTimesheet(w, ts)
+		})
+	},
+}
+
+func init() {
+	reportEstimatesCmd.Flags().String("from", "-30d", "Start date (YYYY-MM-DD, today, -30d, ...)")
+	reportEstimatesCmd.Flags().String("to", "today", "End date (YYYY-MM-DD, today, ...)")
+	reportEstimatesCmd.Flags().Int("worst", 5, "Number of worst misses to list")
+
+	reportCmd.AddCommand(reportEstimatesCmd)
+
+	reportTimesheetCmd.Flags().String("from", "-6d", "Start date (YYYY-MM-DD, today, -7d, ...)")
+	reportTimesheetCmd.Flags().String("to", "today", "End date (YYYY-MM-DD, today, ...)")
+	reportTimesheetCmd.Flags().String("by", "day", "Group by label, list or day")
+	reportTimesheetCmd.Flags().String("format", "table", "Output format: table, csv or json")
+	reportTimesheetCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	reportCmd.AddCommand(reportTimesheetCmd)
+}
+
+func outputEstimateReport(r *report.EstimateReport) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Estimates %s to %s\n", r.From, r.To)
+	fmt.Printf("  Compared %d of %d completed task(s)", r.Overall.Tasks, r.Completed)
+	if r.Unestimated > 0 || r.Untracked > 0 {
+		fmt.Printf(" (%d without estimate, %d without tracked time)", r.Unestimated, r.Untracked)
+	}
+	fmt.Println()
+
+	if r.Overall.Tasks == 0 {
+		return nil
+	}
+	fmt.Printf("  Estimated %s, actual %s, ratio %.2f, average error %.0f%%\n",
+		formatSeconds(r.Overall.Estimated), formatSeconds(r.Overall.Actual), r.Overall.Ratio, r.Overall.Error*100)
+
+	printAccuracy("By label", r.ByLabel)
+	printAccuracy("By list", r.ByList)
+	printAccuracy("By week", r.ByWeek)
+
+	fmt.Println("\nWorst misses:")
+	for _, s := range r.WorstMisses {
+		fmt.Printf("  %7s  %s (%s, estimated %s, actual %s)\n",
+			formatMiss(s.Miss()), s.Description, s.Date, formatSeconds(s.Estimated), formatSeconds(s.Actual))
+	}
+	return nil
+}
+
+func writeTimesheet(w io.Writer, ts *report.Timesheet) error {
+	fmt.Fprintf(w, "Timesheet %s to %s by %s\n", ts.From, ts.To, ts.By)
+	if len(ts.Groups) == 0 {
+		fmt.Fprintln(w, "No tracked time found")
+		return nil
+	}
+
+	for _, g := range ts.Groups {
+		fmt.Fprintf(w, "\n%-50s %8s %6.2fh\n", truncate(g.Name, 50), formatSeconds(g.Seconds), report.Hours(g.Seconds))
+		for _, e := range g.Entries {
+			marker := ""
+			if e.Estimated {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "  %s  %-36s %8s%s\n", e.Date, truncate(e.Description, 36), formatSeconds(e.Seconds), marker)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%-50s %8s %6.2fh\n", "Total", formatSeconds(ts.Seconds), report.Hours(ts.Seconds))
+	if ts.EstimatedSeconds > 0 {
+		fmt.Fprintf(w, "* estimated, no tracked time (%s of the total)\n", formatSeconds(ts.EstimatedSeconds))
+	}
+	return nil
+}
+
+func printAccuracy(title string, groups []report.Accuracy) {
+	fmt.Printf("\n%s:\n", title)
+	for _, g := range groups {
+		fmt.Printf("  %-24s %3d task(s)  ratio %5.2f  error %4.0f%%  %s\n",
+			truncate(g.Name, 24), g.Tasks, g.Ratio, g.Error*100, ratioHint(g.Ratio))
+	}
+}
+
+// ratioHint describes a ratio of actual to estimated time
+func ratioHint(ratio float64) string {
+	switch {
+	case ratio > 1.1:
+		return "underestimated"
+	case ratio < 0.9:
+		return "overestimated"
+	default:
+		return ""
+	}
+}
+
+// formatSeconds formats a duration in seconds as e.g. 1h30m
+func formatSeconds(seconds int) string {
+	return dateutil.FormatMinutes(seconds / 60)
+}
+
+func formatMiss(seconds int) string {
+	if seconds < 0 {
+		return "-" + formatSeconds(-seconds)
+	}
+	return "+" + formatSeconds(seconds)
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+)
+
+// resolver maps label and list names to IDs and back. Labels and lists
+// are fetched lazily and at most once.
+type resolver struct {
+	client *api.Client
+	labels []models.Label
+	lists  []models.List
+
+	labelsLoaded bool
+	listsLoaded  bool
+}
+
+func newResolver(client *api.Client) *resolver {
+	return &resolver{client: client}
+}
+
+func (r *resolver) loadLabels() error {
+	if r.labelsLoaded {
+		return nil
+	}
+	labels, err := r.client.GetLabels()
+	if err != nil {
+		return err
+	}
+	r.labels = labels
+	r.labelsLoaded = true
+	return nil
+}
+
+func (r *resolver) loadLists() error {
+	if r.listsLoaded {
+		return nil
+	}
+	lists, err := r.client.GetLists()
+	if err != nil {
+		return err
+	}
+	r.lists = lists
+	r.listsLoaded = true
+	return nil
+}
+
+// labelID resolves a label given by ID or name, see package resolve
+func (r *resolver) labelID(nameOrID string) (string, error) {
+	labels, err := r.labelMap()
+	if err != nil {
+		return "", err
+	}
+	return resolve.ID(labels, "label", nameOrID)
+}
+
+// listID resolves a list given by ID or title, see package resolve
+func (r *resolver) listID(nameOrID string) (string, error) {
+	lists, err := r.listMap()
+	if err != nil {
+		return "", err
+	}
+	return resolve.ID(lists, "list", nameOrID)
+}
+
+// labelMap returns label names keyed by ID
+func (r *resolver) labelMap() (map[string]string, error) {
+	if err := r.loadLabels(); err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(r.labels))
+	for _, label := range r.labels {
+		names[label.ID] = label.Name
+	}
+	return names, nil
+}
+
+// labelColors returns label colors keyed by ID
+func (r *resolver) labelColors() (map[string]string, error) {
+	if err := r.loadLabels(); err != nil {
+		return nil, err
+	}
+	colors := make(map[string]string, len(r.labels))
+	for _, label := range r.labels {
+		colors[label.ID] = label.Color
+	}
+	return colors, nil
+}
+
+// listMap returns list titles keyed by ID
+func (r *resolver) listMap() (map[string]string, error) {
+	if err := r.loadLists(); err != nil {
+		return nil, err
+	}
+	titles := make(map[string]string, len(r.lists))
+	for _, list := range r.lists {
+		titles[list.ID] = list.Title
+	}
+	return titles, nil
+}
+
+// names returns both list and label names keyed by ID
+func (r *resolver) names() (models.Names, error) {
+	lists, err := r.listMap()
+	if err != nil {
+		return models.Names{}, err
+	}
+	labels, err := r.labelMap()
+	if err != nil {
+		return models.Names{}, err
+	}
+	return models.Names{Lists: lists, Labels: labels}, nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+	"github.com/spf13/cobra"
+)
+
+var rolloverCmd = &cobra.Command{
+	Use:   "rollover",
+	Short: "Move unfinished tasks from past days to a new date",
+	Long:  "Finds incomplete tasks dated in the --since window before --to, and before today, and moves them to --to, or to the braindump with --braindump. Start times are cleared unless --keep-start is given. Recurring instances are left alone; use 'ellie recurring reschedule' for those.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetString("since")
+		to, _ := cmd.Flags().GetString("to")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		keepStart, _ := cmd.Flags().GetBool("keep-start")
+		toBraindump, _ := cmd.Flags().GetBool("braindump")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		days, err := dateutil.ParseDays(since)
+		if err != nil {
+			return err
+		}
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+		now := time.Now().In(loc)
+
+		target, err := dateutil.Parse(to, now)
+		if err != nil {
+			return err
+		}
+		from, until := planner.RolloverWindow(dateutil.Truncate(now), target, days)
+		if until.Before(from) {
+			return fmt.Errorf("nothing to roll over between %s and %s", dateutil.Format(from), dateutil.Format(target))
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		var moves []planner.Rollover
+		recurring := 0
+		for _, date := range dateutil.Range(from, until) {
+			tasks, err := client.GetTasksByDate(date, timeZone)
+			if err != nil {
+				return fmt.Errorf("failed to fetch tasks for %s: %w", date, err)
+			}
+			for _, task := range tasks {
+				if task.Complete {
+					continue
+				}
+				if task.Recurring || task.RecurringID != nil {
+					recurring++
+					continue
+				}
+				moves = append(moves, planner.NewRollover(task, date, dateutil.Format(target), toBraindump, keepStart))
+			}
+		}
+
+		if err := outputRolloverMoves(moves); err != nil {
+			return err
+		}
+		if recurring > 0 && !IsJSONOutput() {
+			fmt.Printf("Left %d recurring instance(s) alone\n", recurring)
+		}
+		if len(moves) == 0 || dryRun {
+			return nil
+		}
+
+		if !yes {
+			ok, err := confirm(fmt.Sprintf("Move %d task(s)?", len(moves)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+
+		var preImages []models.Task
+		var moveErr error
+		for _, move := range moves {
+			if _, err := client.UpdateTask(move.Task.ID, move.Request()); err != nil {
+				moveErr = fmt.Errorf("failed to move task %q: %w", move.Task.Description, err)
+				break
+			}
+			preImages = append(preImages, move.Task)
+		}
+		recordUndo("tasks rollover", journal.ActionModify, preImages)
+
+		if moveErr != nil {
+			return moveErr
+		}
+		if !IsJSONOutput() {
+			fmt.Printf("Moved %d task(s)\n", len(preImages))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rolloverCmd.Flags().String("since", "7d", "How far back to look, e.g. 7d or 2w")
+	rolloverCmd.Flags().String("to", "today", "Date to move tasks to (YYYY-MM-DD, today, tomorrow, ...)")
+	rolloverCmd.Flags().String("timezone", "", "Timezone (e.g., America/New_York)")
+	rolloverCmd.Flags().Bool("keep-start", false, "Keep the tasks' start times")
+	rolloverCmd.Flags().Bool("braindump", false, "Move the tasks to the braindump instead of a date")
+	rolloverCmd.Flags().Bool("dry-run", false, "Only show the tasks that would move")
+	rolloverCmd.Flags().BoolP("yes", "y", false, "Move without asking for confirmation")
+
+	tasksCmd.AddCommand(rolloverCmd)
+}
+
+func outputRolloverMoves(moves []planner.Rollover) error {
+	if IsJSONOutput() {
+		if moves == nil {
+			moves = []planner.Rollover{}
+		}
+		data, err := json.MarshalIndent(moves, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(moves) == 0 {
+		fmt.Println("No unfinished tasks found")
+		return nil
+	}
+
+	for _, m := range moves {
+		to := m.To
+		if to == "" {
+			to = "braindump"
+		}
+		if m.Start != "" {
+			to += " " + m.Start
+		}
+		fmt.Printf("%s -> %s  %s (%s)\n", m.From, to, m.Task.Description, m.Task.ID)
+	}
+	return nil
+}
@@ -35,6 +35,24 @@ func init() {
 	rootCmd.AddCommand(labelsCmd)
 	rootCmd.AddCommand(listsCmd)
 	rootCmd.AddCommand(usersCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(timerCmd)
+	rootCmd.AddCommand(focusCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(recurringCmd)
+	rootCmd.AddCommand(habitsCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(viewCmd)
+	rootCmd.AddCommand(tuiCmd)
 }
 
 // IsJSONOutput returns whether JSON output is enabled
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/config"
+	"github.com/goldie/ellie-cli/internal/ledger"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/todoscan"
+	"github.com/spf13/cobra"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Create tasks from other sources",
+}
+
+var scanTodosCmd = &cobra.Command{
+	Use:   "todos [paths...]",
+	Short: "Create braindump tasks from TODO/FIXME/HACK comments",
+	Long:  "Walks the given directories (default: the current one) and creates a braindump task for every TODO, FIXME and HACK comment, described with its file:line. Comments are remembered by file, tag and text, so re-running the scan only picks up new ones. Tasks get the label from --label or 'ellie config set-scan-label'.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		label, _ := cmd.Flags().GetString("label")
+
+		if label == "" {
+			label = config.GetScanLabel()
+		}
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		scanner, err := todoscan.New(tags)
+		if err != nil {
+			return err
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		// Walk absolute paths so comments are remembered no matter which
+		// directory the scan is run from
+		var comments []todoscan.Comment
+		for _, path := range args {
+			root, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			found, err := scanner.Walk(root)
+			if err != nil {
+				return err
+			}
+			for i := range found {
+				if rel, err := filepath.Rel(cwd, filepath.FromSlash(found[i].Path)); err == nil {
+					found[i].Path = filepath.ToSlash(rel)
+				}
+			}
+			comments = append(comments, found...)
+		}
+
+		book, err := ledger.Open("todos")
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		var labelID string
+		if label != "" {
+			if labelID, err = newResolver(client).labelID(label); err != nil {
+				return err
+			}
+		}
+
+		var planned []importItem
+		seen := make(map[string]bool)
+		for i := range comments {
+			c := &comments[i]
+			if seen[c.Key] {
+				continue
+			}
+			seen[c.Key] = true
+
+			req := &models.CreateTaskRequest{Description: c.Description()}
+			if labelID != "" {
+				req.Label = &labelID
+			}
+			entry := importItem{Key: c.Key, Request: req}
+			if record, ok := book.Get(c.Key); ok {
+				entry.ExistingID = record.TaskID
+			}
+			planned = append(planned, entry)
+		}
+
+		if len(planned) == 0 && !IsJSONOutput() {
+			fmt.Println("No comments found")
+			return nil
+		}
+
+		return runImport(client, book, planned, dryRun)
+	},
+}
+
+func init() {
+	scanTodosCmd.Flags().Bool("dry-run", false, "Show what would be imported without creating tasks")
+	scanTodosCmd.Flags().StringSlice("tags", todoscan.DefaultTags, "Comment tags to look for")
+	scanTodosCmd.Flags().String("label", "", "Label name or ID for created tasks (default: scan_label from config)")
+
+	scanCmd.AddCommand(scanTodosCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/feed"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve task data over HTTP",
+}
+
+var serveICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Serve a live iCalendar feed of upcoming tasks",
+	Long: `Serves an iCalendar feed of upcoming tasks that calendar apps can subscribe to.
+
+The feed is regenerated when the cache expires and supports ETag/If-None-Match.
+Filter with query parameters, e.g. /calendar.ics?list=Work&label=urgent.
+Set ELLIE_FEED_PASSWORD to require basic auth without exposing the password in the process list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		days, _ := cmd.Flags().GetInt("days")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		username, _ := cmd.Flags().GetString("username")
+		password, _ := cmd.Flags().GetString("password")
+		todos, _ := cmd.Flags().GetBool("todos")
+
+		if password == "" {
+			password = os.Getenv("ELLIE_FEED_PASSWORD")
+		}
+		if password != "" && username == "" {
+			return fmt.Errorf("--username is required when a password is set")
+		}
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		server := &feed.Server{
+			Source: feed.Source{
+				Agenda: func(dates []string) ([]models.Task, error) {
+					return fetchAgenda(client, dates)
+				},
+				Labels: client.GetLabels,
+				Lists:  client.GetLists,
+			},
+			Days:     days,
+			TTL:      ttl,
+			Location: loc,
+			Username: username,
+			Password: password,
+			Todos:    todos,
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("/", server)
+
+		httpServer := &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		fmt.Fprintf(os.Stderr, "Serving iCalendar feed on %s\n", addr)
+		return httpServer.ListenAndServe()
+	},
+}
+
+func init() {
+	serveICSCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveICSCmd.Flags().Int("days", 14, "Number of days to include, starting today")
+	serveICSCmd.Flags().Duration("ttl", 5*time.Minute, "How long fetched tasks are cached")
+	serveICSCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+	serveICSCmd.Flags().String("username", "", "Require basic auth with this username")
+	serveICSCmd.Flags().String("password", "", "Basic auth password (or ELLIE_FEED_PASSWORD)")
+	serveICSCmd.Flags().Bool("todos", false, "Serve every task as a to-do, including timed ones")
+
+	serveCmd.AddCommand(serveICSCmd)
+}
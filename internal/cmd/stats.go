@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/chart"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/report"
+	"github.com/spf13/cobra"
+)
+
+const statsBarWidth = 30
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show completion statistics",
+	Long:  "Shows tasks completed per day and week, the completion rate, the average priority of completed tasks, the busiest labels and lists, and streaks of days with at least one completed task.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+		top, _ := cmd.Flags().GetInt("top")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().In(loc)
+		start, end, err := dateutil.ParseRange(from, to, now)
+		if err != nil {
+			return err
+		}
+		dates := dateutil.Range(start, end)
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dates)
+		if err != nil {
+			return err
+		}
+
+		names, err := newResolver(client).names()
+		if err != nil {
+			return err
+		}
+
+		s := report.NewStats(dates, dateutil.Format(now), tasks, names, loc)
+		if top > 0 {
+			if len(s.Labels) > top {
+				s.Labels = s.Labels[:top]
+			}
+			if len(s.Lists) > top {
+				s.Lists = s.Lists[:top]
+			}
+		}
+
+		return outputStats(s)
+	},
+}
+
+func init() {
+	statsCmd.Flags().String("from", "-29d", "Start date (YYYY-MM-DD, today, -30d, ...)")
+	statsCmd.Flags().String("to", "today", "End date (YYYY-MM-DD, today, ...)")
+	statsCmd.Flags().String("timezone", "", "Timezone of completion times (default: local)")
+	statsCmd.Flags().Int("top", 5, "Number of labels and lists to show (0 for all)")
+}
+
+func outputStats(s *report.Stats) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Stats %s to %s\n", s.From, s.To)
+	fmt.Printf("  Completed %d of %d task(s) (%.0f%%)\n", s.Completed, s.Planned, s.CompletionRate*100)
+	if s.AveragePriority > 0 {
+		fmt.Printf("  Average priority: %.1f\n", s.AveragePriority)
+	}
+	fmt.Printf("  Streak: %d day(s), longest %d\n", s.CurrentStreak, s.LongestStreak)
+
+	perDay := make([]int, len(s.Days))
+	best := 0
+	for i, d := range s.Days {
+		perDay[i] = d.Completed
+		if d.Completed > best {
+			best = d.Completed
+		}
+	}
+	fmt.Println("\nCompleted per day:")
+	fmt.Printf("  %s  (max %d)\n", chart.Sparkline(perDay), best)
+
+	fmt.Println("\nCompleted per week:")
+	peak := 0
+	for _, w := range s.Weeks {
+		if w.Completed > peak {
+			peak = w.Completed
+		}
+	}
+	for _, w := range s.Weeks {
+		fmt.Printf("  %s  %-*s %d/%d\n", w.Date, statsBarWidth, chart.Bar(w.Completed, peak, statsBarWidth), w.Completed, w.Planned)
+	}
+
+	printNameCounts("Busiest labels", s.Labels)
+	printNameCounts("Busiest lists", s.Lists)
+	return nil
+}
+
+func printNameCounts(title string, counts []report.NameCount) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", title)
+	peak := counts[0].Completed
+	for _, c := range counts {
+		fmt.Printf("  %-20s %-*s %d/%d\n", truncate(c.Name, 20), statsBarWidth, chart.Bar(c.Completed, peak, statsBarWidth), c.Completed, c.Planned)
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/markdown"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Apply changes made in exported files back to tasks",
+}
+
+var syncMarkdownCmd = &cobra.Command{
+	Use:   "markdown <file>",
+	Short: "Apply ticked checkboxes and edited descriptions from a Markdown note",
+	Long:  "Reads checklist lines written by 'ellie export markdown', detects checkboxes and descriptions changed since the export, and applies them to the tasks after showing a preview.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open note: %w", err)
+		}
+		entries, err := markdown.Parse(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks := make(map[string]*models.Task)
+		for _, entry := range entries {
+			if _, ok := tasks[entry.TaskID]; ok {
+				continue
+			}
+			task, err := client.GetTask(entry.TaskID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: line %d: task %s: %v\n", entry.Line, entry.TaskID, err)
+				continue
+			}
+			tasks[entry.TaskID] = task
+		}
+
+		changes := markdown.Diff(entries, tasks)
+		if err := outputSyncChanges(changes); err != nil {
+			return err
+		}
+		if len(changes) == 0 || dryRun {
+			return nil
+		}
+
+		if !yes {
+			ok, err := confirm(fmt.Sprintf("Apply %d change(s)?", len(changes)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+
+		var preImages []models.Task
+		var syncErr error
+		for _, change := range changes {
+			if err := applySyncChange(client, &change); err != nil {
+				syncErr = fmt.Errorf("failed to update task %s: %w", change.Task.ID, err)
+				break
+			}
+			preImages = append(preImages, *change.Task)
+		}
+		recordUndo("sync markdown", journal.ActionModify, preImages)
+
+		if syncErr != nil {
+			return syncErr
+		}
+		if !IsJSONOutput() {
+			fmt.Printf("Applied %d change(s)\n", len(preImages))
+		}
+		return nil
+	},
+}
+
+func init() {
+	syncMarkdownCmd.Flags().Bool("dry-run", false, "Only show the changes")
+	syncMarkdownCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+
+	syncCmd.AddCommand(syncMarkdownCmd)
+}
+
+// applySyncChange completes, reopens or renames a task. Completing goes
+// through MarkTaskComplete; everything else is a single UpdateTask.
+func applySyncChange(client *api.Client, change *markdown.Change) error {
+	if change.Complete != nil && *change.Complete && change.Description == nil {
+		_, err := client.MarkTaskComplete(change.Task.ID)
+		return err
+	}
+
+	req := &models.UpdateTaskRequest{
+		Complete:    change.Complete,
+		Description: change.Description,
+	}
+	_, err := client.UpdateTask(change.Task.ID, req)
+	return err
+}
+
+func outputSyncChanges(changes []markdown.Change) error {
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No changes found")
+		return nil
+	}
+
+	for _, change := range changes {
+		fmt.Printf("line %d: %s\n", change.Entry.Line, change.Task.Description)
+		if change.Complete != nil {
+			if *change.Complete {
+				fmt.Println("    [ ] -> [x]")
+			} else {
+				fmt.Println("    [x] -> [ ]")
+			}
+		}
+		if change.Description != nil {
+			fmt.Printf("    description -> %q\n", *change.Description)
+		}
+	}
+	fmt.Println()
+	return nil
+}
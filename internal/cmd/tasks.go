@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/journal"
 	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
 	"github.com/spf13/cobra"
 )
 
@@ -144,11 +146,19 @@ var createTaskCmd = &cobra.Command{
 			return err
 		}
 
+		var warning string
+		if date != "" {
+			warning = capacityWarning(client, date, planner.Minutes(estimatedTime))
+		}
+
 		task, err := client.CreateTask(req)
 		if err != nil {
 			return err
 		}
 
+		if warning != "" {
+			printWarnings("capacity", []string{warning})
+		}
 		return outputTask(task)
 	},
 }
@@ -201,10 +211,16 @@ var updateTaskCmd = &cobra.Command{
 			return err
 		}
 
+		preImages, err := snapshotTasks(client, taskID)
+		if err != nil {
+			return err
+		}
+
 		task, err := client.UpdateTask(taskID, req)
 		if err != nil {
 			return err
 		}
+		recordUndo("tasks update", journal.ActionModify, preImages)
 
 		return outputTask(task)
 	},
@@ -220,10 +236,16 @@ var completeTaskCmd = &cobra.Command{
 			return err
 		}
 
+		preImages, err := snapshotTasks(client, args[0])
+		if err != nil {
+			return err
+		}
+
 		task, err := client.MarkTaskComplete(args[0])
 		if err != nil {
 			return err
 		}
+		recordUndo("tasks complete", journal.ActionModify, preImages)
 
 		return outputTask(task)
 	},
@@ -239,9 +261,15 @@ var deleteTaskCmd = &cobra.Command{
 			return err
 		}
 
+		preImages, err := snapshotTasks(client, args[0])
+		if err != nil {
+			return err
+		}
+
 		if err := client.DeleteTask(args[0]); err != nil {
 			return err
 		}
+		recordUndo("tasks delete", journal.ActionDelete, preImages)
 
 		if !IsJSONOutput() {
 			fmt.Println("Task deleted successfully")
@@ -398,6 +426,13 @@ func printTask(task *models.Task) {
 		}
 	}
 
+	if task.ActualTime != nil && *task.ActualTime > 0 {
+		minutes := *task.ActualTime / 60
+		if minutes > 0 {
+			fmt.Printf("    Actual: %d min\n", minutes)
+		}
+	}
+
 	if task.Priority != nil {
 		fmt.Printf("    Priority: %s\n", priorityString(*task.Priority))
 	}
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/timer"
+	"github.com/spf13/cobra"
+)
+
+var timerCmd = &cobra.Command{
+	Use:   "timer",
+	Short: "Track actual time spent on tasks",
+	Long:  "A single local timer shared by all shells. Stopping it adds the elapsed time to the task's actual time.",
+}
+
+var timerStartCmd = &cobra.Command{
+	Use:   "start <task-id>",
+	Short: "Start timing a task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		task, err := client.GetTask(args[0])
+		if err != nil {
+			return err
+		}
+
+		store, err := timer.Open()
+		if err != nil {
+			return err
+		}
+
+		var session timer.Session
+		err = store.Update(func(st *timer.State) error {
+			if err := st.Start(task.ID, task.Description, time.Now()); err != nil {
+				return err
+			}
+			session = *st.Active
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return outputTimerStatus(&session)
+	},
+}
+
+var timerPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause the running timer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateTimer(func(st *timer.State) error {
+			return st.Pause(time.Now())
+		})
+	},
+}
+
+var timerResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a paused timer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateTimer(func(st *timer.State) error {
+			return st.Resume(time.Now())
+		})
+	},
+}
+
+var timerStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the timer and add the time to the task",
+	Long:  "Stops the timer and adds the elapsed time to the task's actual time. Time that could not be saved earlier, e.g. because the API was unreachable, is retried as well.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := timer.Open()
+		if err != nil {
+			return err
+		}
+
+		err = store.Update(func(st *timer.State) error {
+			now := time.Now()
+			if st.FocusRunning(now) {
+				return errFocusRunning
+			}
+			_, err := st.Stop(now)
+			return err
+		})
+		if err != nil && !errors.Is(err, timer.ErrNoTimer) {
+			return err
+		}
+		stopped := err == nil
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		saved, err := saveTimerEntries(client, store, "timer stop")
+		if len(saved) == 0 && err == nil && !stopped {
+			return timer.ErrNoTimer
+		}
+		if IsJSONOutput() {
+			if outErr := outputTimerEntries(saved); outErr != nil {
+				return outErr
+			}
+			return err
+		}
+		for _, e := range saved {
+			fmt.Printf("Added %s to %s\n", formatClock(time.Duration(e.Seconds)*time.Second), e.Description)
+		}
+		return err
+	},
+}
+
+var timerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running timer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := timer.Open()
+		if err != nil {
+			return err
+		}
+
+		st, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		if st.Active == nil {
+			if st.Focus != nil && st.FocusRunning(time.Now()) && !IsJSONOutput() {
+				fmt.Println(st.Focus.StatusLine(time.Now()))
+				return nil
+			}
+			if IsJSONOutput() {
+				fmt.Println("null")
+				return nil
+			}
+			fmt.Println("No timer running")
+			if n := len(st.Unsaved()); n > 0 {
+				fmt.Printf("%d session(s) not yet saved, run 'ellie timer stop' to retry\n", n)
+			}
+			return nil
+		}
+
+		return outputTimerStatus(st.Active)
+	},
+}
+
+var timerLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show finished timer sessions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		taskID, _ := cmd.Flags().GetString("task")
+
+		store, err := timer.Open()
+		if err != nil {
+			return err
+		}
+
+		st, err := store.Load()
+		if err != nil {
+			return err
+		}
+
+		var entries []timer.Entry
+		for _, e := range st.Log {
+			if taskID == "" || e.TaskID == taskID {
+				entries = append(entries, e)
+			}
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		return outputTimerEntries(entries)
+	},
+}
+
+func init() {
+	timerLogCmd.Flags().Int("limit", 20, "Number of sessions to show (0 for all)")
+	timerLogCmd.Flags().String("task", "", "Only show sessions of this task ID")
+
+	timerCmd.AddCommand(timerStartCmd)
+	timerCmd.AddCommand(timerPauseCmd)
+	timerCmd.AddCommand(timerResumeCmd)
+	timerCmd.AddCommand(timerStopCmd)
+	timerCmd.AddCommand(timerStatusCmd)
+	timerCmd.AddCommand(timerLogCmd)
+}
+
+var errFocusRunning = errors.New("a focus session is using the timer, end it with Ctrl-C in its terminal")
+
+// updateTimer applies fn to the timer state and prints the result
+func updateTimer(fn func(*timer.State) error) error {
+	store, err := timer.Open()
+	if err != nil {
+		return err
+	}
+
+	var session timer.Session
+	err = store.Update(func(st *timer.State) error {
+		if st.FocusRunning(time.Now()) {
+			return errFocusRunning
+		}
+		if err := fn(st); err != nil {
+			return err
+		}
+		session = *st.Active
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return outputTimerStatus(&session)
+}
+
+// saveTimerEntries adds the time of every unsaved log entry to its task's
+// actual time. The entries are claimed as saved before the API is called,
+// so that a concurrent run does not add them again, and released if they
+// could not be saved. All touched tasks are recorded as one undo journal
+// entry.
+func saveTimerEntries(client *api.Client, store *timer.Store, command string) ([]timer.Entry, error) {
+	var claimed []timer.Entry
+	if err := store.Update(func(st *timer.State) error {
+		claimed = st.ClaimUnsaved()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var saved []timer.Entry
+	var preImages []models.Task
+	var saveErr error
+	for _, entry := range claimed {
+		task, err := client.GetTask(entry.TaskID)
+		if err != nil {
+			saveErr = fmt.Errorf("failed to save time for %q: %w", entry.Description, err)
+			break
+		}
+		actual := entry.Seconds
+		if task.ActualTime != nil {
+			actual += *task.ActualTime
+		}
+		if _, err := client.UpdateTask(task.ID, &models.UpdateTaskRequest{ActualTime: &actual}); err != nil {
+			saveErr = fmt.Errorf("failed to save time for %q: %w", entry.Description, err)
+			break
+		}
+
+		preImages = append(preImages, *task)
+		saved = append(saved, entry)
+	}
+
+	if failed := claimed[len(saved):]; len(failed) > 0 {
+		if err := store.Update(func(st *timer.State) error {
+			st.Release(failed)
+			return nil
+		}); err != nil && saveErr == nil {
+			saveErr = err
+		}
+	}
+	if len(saved) > 0 {
+		recordUndo(command, journal.ActionModify, preImages)
+	}
+
+	return saved, saveErr
+}
+
+func outputTimerStatus(session *timer.Session) error {
+	elapsed := session.ElapsedAt(time.Now())
+
+	if IsJSONOutput() {
+		data, err := json.MarshalIndent(struct {
+			*timer.Session
+			ElapsedSeconds int `json:"elapsed_seconds"`
+		}{session, int(elapsed / time.Second)}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	state := "running"
+	if session.Paused {
+		state = "paused"
+	}
+	fmt.Printf("%s %s (%s)\n", formatClock(elapsed), session.Description, state)
+	fmt.Printf("    Task: %s\n", session.TaskID)
+	fmt.Printf("    Started: %s\n", session.StartedAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+func outputTimerEntries(entries []timer.Entry) error {
+	if IsJSONOutput() {
+		if entries == nil {
+			entries = []timer.Entry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No timer sessions found")
+		return nil
+	}
+
+	for _, e := range entries {
+		status := ""
+		if !e.Saved {
+			status = " (not saved)"
+		}
+		fmt.Printf("#%d %s  %s  %s%s\n", e.ID, e.Start.Local().Format("2006-01-02 15:04"), formatClock(time.Duration(e.Seconds)*time.Second), e.Description, status)
+	}
+	return nil
+}
+
+// formatClock formats a duration as H:MM:SS
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/journal"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen interface for the agenda, braindump and lists",
+	Long: `Opens a full-screen interface with the agenda of a day, the braindump and the tasks of a list.
+
+  j/k, arrows      move within a pane        tab, h/l       switch pane
+  x, space         complete or reopen         e, enter       edit the description
+  r                reschedule (date [HH:MM], empty for the braindump)
+  H/L              move a task a day back or forward, the agenda follows it
+  m                move a task to the agenda's day    b    move it to the braindump
+  < >  t           previous, next day, today          [ ]  previous, next list
+  /                search all panes as you type       esc  clear the search
+  R                reload                             q    quit
+
+Changes show at once and are saved in the background; if saving fails the task is put
+back. Every saved change can be undone with 'ellie undo'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+		timeZone, _ := cmd.Flags().GetString("timezone")
+
+		loc, err := loadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+
+		day, err := dateutil.Parse(date, time.Now().In(loc))
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		// Warnings cannot be printed while the interface owns the screen
+		var journalErr error
+		model := tui.New(client, tui.Options{
+			Date:     dateutil.Format(day),
+			Location: loc,
+			OnChange: func(action string, before models.Task) {
+				if err := writeUndo("tui "+action, journal.ActionModify, []models.Task{before}); err != nil {
+					journalErr = err
+				}
+			},
+		})
+
+		err = tui.Run(model, os.Stdin, os.Stdout)
+		if journalErr != nil {
+			warnUndo(journalErr)
+		}
+		return err
+	},
+}
+
+func init() {
+	tuiCmd.Flags().String("date", "today", "Day shown in the agenda (YYYY-MM-DD, today, tomorrow, ...)")
+	tuiCmd.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/api"
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/planner"
+	"github.com/goldie/ellie-cli/internal/term"
+	"github.com/goldie/ellie-cli/internal/view"
+	"github.com/spf13/cobra"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show tasks as a timeline or calendar",
+}
+
+var viewDayCmd = &cobra.Command{
+	Use:   "day",
+	Short: "Show a day as an hour-by-hour timeline",
+	Long:  "Draws the day's timed tasks on an hour timeline, each as tall as its estimate, with overlapping tasks side by side. Tasks without a start time are listed below. Tasks are colored by their label's color.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+		workHours, _ := cmd.Flags().GetString("work-hours")
+		slot, _ := cmd.Flags().GetDuration("slot")
+
+		hours, err := planner.ParseWindow(workHours)
+		if err != nil {
+			return err
+		}
+		if slot < time.Minute {
+			return fmt.Errorf("--slot must be at least 1m")
+		}
+
+		opts, err := viewOptions(cmd)
+		if err != nil {
+			return err
+		}
+		opts.Hours = hours
+		opts.Slot = int(slot / time.Minute)
+
+		day, err := dateutil.Parse(date, time.Now().In(opts.Location))
+		if err != nil {
+			return err
+		}
+		dayStr := dateutil.Format(day)
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, []string{dayStr})
+		if err != nil {
+			return err
+		}
+		if IsJSONOutput() {
+			return outputTasks(tasks)
+		}
+
+		if err := loadViewColors(client, &opts); err != nil {
+			return err
+		}
+		printLines(view.Day(dayStr, tasks, opts))
+		return nil
+	},
+}
+
+var viewWeekCmd = &cobra.Command{
+	Use:   "week",
+	Short: "Show a week as a seven-column calendar",
+	Long:  "Shows the week from Monday to Sunday containing --date, one column per day with timed tasks first and the total estimate at the bottom. Tasks are colored by their label's color.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+
+		opts, err := viewOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().In(opts.Location)
+		day, err := dateutil.Parse(date, now)
+		if err != nil {
+			return err
+		}
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		dates := dateutil.Range(monday, monday.AddDate(0, 0, 6))
+		opts.Today = dateutil.Format(now)
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		tasks, err := fetchAgenda(client, dates)
+		if err != nil {
+			return err
+		}
+		if IsJSONOutput() {
+			return outputTasks(tasks)
+		}
+
+		if err := loadViewColors(client, &opts); err != nil {
+			return err
+		}
+		printLines(view.Week(dates, tasks, opts))
+		return nil
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{viewDayCmd, viewWeekCmd} {
+		c.Flags().String("date", "today", "Date to show (YYYY-MM-DD, today, tomorrow, ...)")
+		c.Flags().String("timezone", "", "Timezone of task start times (default: local)")
+		c.Flags().Int("width", 0, "Width in columns (default: terminal width)")
+		c.Flags().Bool("no-color", false, "Do not color tasks by label")
+	}
+	viewDayCmd.Flags().String("work-hours", "08:00-18:00", "Hours always shown on the timeline")
+	viewDayCmd.Flags().Duration("slot", 30*time.Minute, "Time per row of the timeline")
+
+	viewCmd.AddCommand(viewDayCmd)
+	viewCmd.AddCommand(viewWeekCmd)
+}
+
+// viewOptions reads the flags shared by the view commands
+func viewOptions(cmd *cobra.Command) (view.Options, error) {
+	timeZone, _ := cmd.Flags().GetString("timezone")
+	width, _ := cmd.Flags().GetInt("width")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+
+	loc, err := loadLocation(timeZone)
+	if err != nil {
+		return view.Options{}, err
+	}
+	if width <= 0 {
+		width = term.Width(os.Stdout)
+	}
+	return view.Options{
+		Width:    width,
+		Color:    !noColor && term.ColorEnabled(os.Stdout),
+		Location: loc,
+	}, nil
+}
+
+func loadViewColors(client *api.Client, opts *view.Options) error {
+	if !opts.Color {
+		return nil
+	}
+	colors, err := newResolver(client).labelColors()
+	if err != nil {
+		return err
+	}
+	opts.LabelColors = colors
+	return nil
+}
+
+func printLines(lines []string) {
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
@@ -17,8 +17,9 @@ const (
 
 // Config holds the application configuration
 type Config struct {
-	APIKey  string `mapstructure:"api_key"`
-	BaseURL string `mapstructure:"base_url"`
+	APIKey    string `mapstructure:"api_key"`
+	BaseURL   string `mapstructure:"base_url"`
+	ScanLabel string `mapstructure:"scan_label"`
 }
 
 // DefaultBaseURL is the default API base URL
@@ -131,11 +132,60 @@ func SetBaseURL(baseURL string) error {
 	return nil
 }
 
+// GetScanLabel returns the label given to tasks created from code comments
+func GetScanLabel() string {
+	return viper.GetString("scan_label")
+}
+
+// SetScanLabel saves the label for tasks created from code comments
+func SetScanLabel(label string) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+
+	viper.Set("scan_label", label)
+
+	configPath := filepath.Join(configDir, configFileName+"."+configFileType)
+	if err := viper.WriteConfigAs(configPath); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// GetCapacity returns the working minutes configured for a weekday such
+// as "mon", and whether one is configured at all
+func GetCapacity(day string) (int, bool) {
+	key := "capacity." + day
+	return viper.GetInt(key), viper.IsSet(key)
+}
+
+// SetCapacity saves the working minutes for the given weekdays
+func SetCapacity(days []string, minutes int) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		viper.Set("capacity."+day, minutes)
+	}
+
+	configPath := filepath.Join(configDir, configFileName+"."+configFileType)
+	if err := viper.WriteConfigAs(configPath); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
 // GetConfig returns the current configuration
 func GetConfig() *Config {
 	apiKey, _ := GetAPIKey()
 	return &Config{
-		APIKey:  apiKey,
-		BaseURL: GetBaseURL(),
+		APIKey:    apiKey,
+		BaseURL:   GetBaseURL(),
+		ScanLabel: GetScanLabel(),
 	}
 }
@@ -0,0 +1,170 @@
+package dateutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the date format used by the ELLIE API
+const Layout = "2006-01-02"
+
+// Parse resolves a date argument relative to now. Accepted forms are
+// YYYY-MM-DD, "today", "tomorrow", "yesterday" and signed offsets such
+// as "-7d", "+2w" or "3d".
+func Parse(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := Truncate(now)
+
+	switch s {
+	case "", "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if days, ok := parseOffset(s); ok {
+		return today.AddDate(0, 0, days), nil
+	}
+
+	date, err := time.ParseInLocation(Layout, s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD, today, tomorrow, yesterday or an offset like -7d", s)
+	}
+	return date, nil
+}
+
+// ParseDays parses a day count such as "7d", "2w" or "7"
+func ParseDays(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+		return n, nil
+	}
+	if days, ok := parseOffset(s); ok && days >= 0 && !strings.HasPrefix(s, "+") {
+		return days, nil
+	}
+	return 0, fmt.Errorf("invalid duration %q: expected a number of days like 7d or 2w", s)
+}
+
+func parseOffset(s string) (int, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+
+	multiplier := 0
+	switch s[len(s)-1] {
+	case 'd':
+		multiplier = 1
+	case 'w':
+		multiplier = 7
+	default:
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(s[:len(s)-1], "+"))
+	if err != nil {
+		return 0, false
+	}
+	return n * multiplier, true
+}
+
+// Truncate returns midnight of the day containing t in t's location
+func Truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Format formats a time as an API date string
+func Format(t time.Time) string {
+	return t.Format(Layout)
+}
+
+// Range returns every date from from to to inclusive as API date strings
+func Range(from, to time.Time) []string {
+	var dates []string
+	for d := Truncate(from); !d.After(to); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, Format(d))
+	}
+	return dates
+}
+
+// ParseRange parses a from/to pair and validates its order
+func ParseRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	start, err := Parse(from, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := Parse(to, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to date %s is before --from date %s", Format(end), Format(start))
+	}
+	return start, end, nil
+}
+
+// ParseStart interprets a task start value. Full timestamps with an offset
+// are converted to loc; bare times like "09:30" and timestamps without an
+// offset are taken as wall-clock time on date in loc.
+func ParseStart(date, start string, loc *time.Location) (time.Time, bool) {
+	start = strings.TrimSpace(start)
+	if start == "" {
+		return time.Time{}, false
+	}
+
+	if t, err := time.Parse(time.RFC3339, start); err == nil {
+		return t.In(loc), true
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, start, loc); err == nil {
+			return t, true
+		}
+	}
+
+	if date == "" {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation(Layout, date, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if clock, err := time.Parse(layout, start); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ParseDay parses the date part of a value that is either a plain date or
+// a full timestamp
+func ParseDay(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return Truncate(t.In(loc)), true
+	}
+	if len(s) >= len(Layout) {
+		if t, err := time.ParseInLocation(Layout, s[:len(Layout)], loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FormatMinutes formats a duration in minutes as e.g. 45m or 1h30m
+func FormatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,122 @@
+package dateutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 1, 15, 13, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"today", "2024-01-15"},
+		{"", "2024-01-15"},
+		{"tomorrow", "2024-01-16"},
+		{"yesterday", "2024-01-14"},
+		{"-7d", "2024-01-08"},
+		{"+2w", "2024-01-29"},
+		{"3d", "2024-01-18"},
+		{"2024-02-29", "2024-02-29"},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if Format(got) != tt.expected {
+			t.Errorf("Parse(%q): expected %s, got %s", tt.input, tt.expected, Format(got))
+		}
+	}
+
+	if _, err := Parse("next week", now); err == nil {
+		t.Error("expected error for unsupported date")
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	tests := map[string]int{"7d": 7, "2w": 14, "3": 3}
+	for input, expected := range tests {
+		got, err := ParseDays(input)
+		if err != nil {
+			t.Errorf("ParseDays(%q): unexpected error: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseDays(%q): expected %d, got %d", input, expected, got)
+		}
+	}
+
+	if _, err := ParseDays("-7d"); err == nil {
+		t.Error("expected error for negative duration")
+	}
+}
+
+func TestRange(t *testing.T) {
+	from := time.Date(2024, 2, 27, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	dates := Range(from, to)
+	expected := []string{"2024-02-27", "2024-02-28", "2024-02-29", "2024-03-01"}
+	if len(dates) != len(expected) {
+		t.Fatalf("expected %d dates, got %d", len(expected), len(dates))
+	}
+	for i := range expected {
+		if dates[i] != expected[i] {
+			t.Errorf("expected %s at %d, got %s", expected[i], i, dates[i])
+		}
+	}
+}
+
+func TestParseStart(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	tests := []struct {
+		date     string
+		start    string
+		expected string
+	}{
+		{"2024-01-15", "09:30", "2024-01-15T08:30:00Z"},
+		{"2024-07-15", "09:30", "2024-07-15T07:30:00Z"},
+		{"", "2024-01-15T09:30:00Z", "2024-01-15T09:30:00Z"},
+		{"", "2024-01-15T09:30:00", "2024-01-15T08:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseStart(tt.date, tt.start, berlin)
+		if !ok {
+			t.Errorf("ParseStart(%q, %q): expected success", tt.date, tt.start)
+			continue
+		}
+		if got.UTC().Format(time.RFC3339) != tt.expected {
+			t.Errorf("ParseStart(%q, %q): expected %s, got %s", tt.date, tt.start, tt.expected, got.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if _, ok := ParseStart("", "09:30", berlin); ok {
+		t.Error("expected failure for bare time without date")
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := map[int]string{
+		0:   "0m",
+		45:  "45m",
+		60:  "1h",
+		90:  "1h30m",
+		480: "8h",
+	}
+
+	for minutes, expected := range tests {
+		if got := FormatMinutes(minutes); got != expected {
+			t.Errorf("FormatMinutes(%d): expected %s, got %s", minutes, expected, got)
+		}
+	}
+}
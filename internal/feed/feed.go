@@ -0,0 +1,273 @@
+package feed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/ical"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+)
+
+// Source provides the data a feed is built from
+type Source struct {
+	// Agenda returns the tasks for the given dates, with recurring
+	// instances stamped with the date they occur on
+	Agenda func(dates []string) ([]models.Task, error)
+	Labels func() ([]models.Label, error)
+	Lists  func() ([]models.List, error)
+}
+
+// Server serves an iCalendar feed of upcoming tasks. Fetched data is
+// cached for TTL so calendar clients polling the feed do not exhaust
+// the API rate limit.
+type Server struct {
+	Source   Source
+	Days     int
+	TTL      time.Duration
+	Location *time.Location
+	Username string
+	Password string
+	Todos    bool
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+
+	mu       sync.Mutex
+	snapshot *snapshot
+}
+
+type snapshot struct {
+	fetchedAt time.Time
+	tasks     []models.Task
+	labels    []models.Label
+	lists     []models.List
+}
+
+// ServeHTTP renders the feed. The list and label query parameters take
+// IDs or names, may be repeated or comma separated, and restrict the
+// feed to matching tasks.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="ellie"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	snap, err := s.load()
+	if err != nil {
+		log.Printf("failed to build feed: %v", err)
+		http.Error(w, "failed to fetch tasks", http.StatusBadGateway)
+		return
+	}
+
+	names := models.Names{
+		Lists:  make(map[string]string, len(snap.lists)),
+		Labels: make(map[string]string, len(snap.labels)),
+	}
+	for _, list := range snap.lists {
+		names.Lists[list.ID] = list.Title
+	}
+	for _, label := range snap.labels {
+		names.Labels[label.ID] = label.Name
+	}
+
+	query := r.URL.Query()
+	listIDs, err := resolveIDs(queryValues(query["list"]), names, resolve.ListID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	labelIDs, err := resolveIDs(queryValues(query["label"]), names, resolve.LabelID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tasks := filterTasks(snap.tasks, listIDs, labelIDs)
+
+	cal := ical.FromTasks(tasks, ical.Options{
+		Location:   s.Location,
+		Now:        snap.fetchedAt,
+		LabelNames: names.Labels,
+		Todos:      s.Todos,
+		Name:       "Ellie",
+	})
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		http.Error(w, "failed to render feed", http.StatusInternalServerError)
+		return
+	}
+
+	etag, err := taskETag(tasks, names.Labels)
+	if err != nil {
+		http.Error(w, "failed to render feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(s.TTL/time.Second)))
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="ellie.ics"`)
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Username == "" && s.Password == "" {
+		return true
+	}
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	userOK := subtle.ConstantTimeCompare([]byte(user), []byte(s.Username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(s.Password)) == 1
+	return userOK && passOK
+}
+
+// load returns cached data, refreshing it once the TTL has expired
+func (s *Server) load() (*snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.snapshot != nil && now.Sub(s.snapshot.fetchedAt) < s.TTL {
+		return s.snapshot, nil
+	}
+
+	snap := &snapshot{fetchedAt: now}
+
+	var err error
+	if snap.labels, err = s.Source.Labels(); err != nil {
+		return nil, err
+	}
+	if snap.lists, err = s.Source.Lists(); err != nil {
+		return nil, err
+	}
+
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	start := dateutil.Truncate(now.In(loc))
+	days := s.Days
+	if days < 1 {
+		days = 1
+	}
+
+	snap.tasks, err = s.Source.Agenda(dateutil.Range(start, start.AddDate(0, 0, days-1)))
+	if err != nil {
+		return nil, err
+	}
+
+	s.snapshot = snap
+	return snap, nil
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func filterTasks(tasks []models.Task, listIDs, labelIDs map[string]bool) []models.Task {
+	if listIDs == nil && labelIDs == nil {
+		return tasks
+	}
+
+	var filtered []models.Task
+	for _, task := range tasks {
+		if listIDs != nil && (task.ListID == nil || !listIDs[*task.ListID]) {
+			continue
+		}
+		if labelIDs != nil && (task.Label == nil || !labelIDs[*task.Label]) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+	return filtered
+}
+
+// resolveIDs maps filter values, given as IDs or names, to a set of IDs
+// with one of the resolve functions. It returns nil when no filter was
+// given.
+func resolveIDs(values []string, names models.Names, resolveID func(models.Names, string) (string, error)) (map[string]bool, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	ids := make(map[string]bool)
+	for _, value := range values {
+		id, err := resolveID(names, value)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+func queryValues(raw []string) []string {
+	var values []string
+	for _, v := range raw {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// taskETag identifies the feed by the data it is rendered from. Hashing
+// the output instead would change the tag on every refresh, since
+// DTSTAMP is the time the data was fetched.
+func taskETag(tasks []models.Task, labelNames map[string]string) (string, error) {
+	data, err := json.Marshal(struct {
+		Tasks  []models.Task     `json:"tasks"`
+		Labels map[string]string `json:"labels"`
+	}{tasks, labelNames})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package feed
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func setupTestFeed(t *testing.T, fetches *int) *Server {
+	now := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+
+	return &Server{
+		Source: Source{
+			Agenda: func(dates []string) ([]models.Task, error) {
+				*fetches++
+				if dates[0] != "2024-01-15" || len(dates) != 2 {
+					t.Errorf("unexpected dates: %v", dates)
+				}
+				return []models.Task{
+					{ID: "work-task", Description: "Review", Date: json.RawMessage(`"2024-01-15"`), Label: strPtr("label-work")},
+					{ID: "home-task", Description: "Laundry", Date: json.RawMessage(`"2024-01-16"`), ListID: strPtr("list-home")},
+				}, nil
+			},
+			Labels: func() ([]models.Label, error) {
+				return []models.Label{{ID: "label-work", Name: "Work"}}, nil
+			},
+			Lists: func() ([]models.List, error) {
+				return []models.List{{ID: "list-home", Title: "Home"}}, nil
+			},
+		},
+		Days:     2,
+		TTL:      time.Minute,
+		Location: time.UTC,
+		Now:      func() time.Time { return now },
+	}
+}
+
+func TestServer_CachesAndETag(t *testing.T) {
+	fetches := 0
+	server := setupTestFeed(t, &fetches)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Errorf("unexpected content type: %s", rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "SUMMARY:Review") || !strings.Contains(body, "SUMMARY:Laundry") {
+		t.Errorf("expected both tasks in feed, got:\n%s", body)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar.ics", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304, got %d", rec.Code)
+	}
+	if fetches != 1 {
+		t.Errorf("expected 1 fetch within TTL, got %d", fetches)
+	}
+}
+
+func TestServer_ETagSurvivesRefresh(t *testing.T) {
+	fetches := 0
+	server := setupTestFeed(t, &fetches)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	etag := rec.Header().Get("ETag")
+
+	// The cache expires and the unchanged tasks are fetched again with a
+	// new DTSTAMP
+	later := time.Date(2024, 1, 15, 8, 5, 0, 0, time.UTC)
+	server.Now = func() time.Time { return later }
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar.ics", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if fetches != 2 {
+		t.Errorf("expected a refresh after the TTL, got %d fetch(es)", fetches)
+	}
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for unchanged tasks, got %d", rec.Code)
+	}
+}
+
+func TestServer_Filters(t *testing.T) {
+	fetches := 0
+	server := setupTestFeed(t, &fetches)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?label=work", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "Review") || strings.Contains(body, "Laundry") {
+		t.Errorf("expected only the work task, got:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?list=list-home", nil))
+	if body := rec.Body.String(); strings.Contains(body, "Review") || !strings.Contains(body, "Laundry") {
+		t.Errorf("expected only the home task, got:\n%s", body)
+	}
+}
+
+func TestServer_UnknownFilter(t *testing.T) {
+	fetches := 0
+	server := setupTestFeed(t, &fetches)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?label=errands", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `label "errands" not found`) {
+		t.Errorf("expected resolve error, got: %s", rec.Body.String())
+	}
+}
+
+func TestServer_BasicAuth(t *testing.T) {
+	fetches := 0
+	server := setupTestFeed(t, &fetches)
+	server.Username = "me"
+	server.Password = "secret"
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without credentials, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("me", "secret")
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with credentials, got %d", rec.Code)
+	}
+}
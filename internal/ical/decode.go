@@ -0,0 +1,129 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Decode parses an iCalendar stream and returns its top-level component
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []*Component
+	var root *Component
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch strings.ToUpper(prop.Name) {
+		case "BEGIN":
+			stack = append(stack, &Component{Name: strings.ToUpper(prop.Value)})
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			done := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				if root == nil {
+					root = done
+				}
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.Components = append(parent.Components, *done)
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property outside of a component", i+1)
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, prop)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no calendar found")
+	}
+	return root, nil
+}
+
+// unfold joins continuation lines that start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine splits a content line into name, parameters and value,
+// honouring quoted parameter values that may contain ':' or ';'
+func parseLine(line string) (Property, error) {
+	var prop Property
+
+	inQuotes := false
+	valueStart := -1
+	var segments []string
+	segStart := 0
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				segments = append(segments, line[segStart:i])
+				segStart = i + 1
+			}
+		case ':':
+			if !inQuotes {
+				segments = append(segments, line[segStart:i])
+				valueStart = i + 1
+			}
+		}
+		if valueStart >= 0 {
+			break
+		}
+	}
+
+	if valueStart < 0 || len(segments) == 0 || segments[0] == "" {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+
+	prop.Name = strings.ToUpper(segments[0])
+	prop.Value = line[valueStart:]
+	for _, seg := range segments[1:] {
+		name, value, _ := strings.Cut(seg, "=")
+		prop.Params = append(prop.Params, Param{
+			Name:  strings.ToUpper(name),
+			Value: strings.Trim(value, `"`),
+		})
+	}
+	return prop, nil
+}
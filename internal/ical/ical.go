@@ -0,0 +1,145 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the line length limit from RFC 5545 section 3.1
+const maxLineOctets = 75
+
+// Param is a property parameter such as VALUE=DATE
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Property is a single content line of a component
+type Property struct {
+	Name   string
+	Params []Param
+	Value  string
+}
+
+// Param returns the value of the named parameter
+func (p *Property) Param(name string) string {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, name) {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// Component is an iCalendar component such as VCALENDAR, VEVENT or VTODO
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+}
+
+// Add appends a property with an already escaped value
+func (c *Component) Add(name, value string, params ...Param) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText appends a TEXT property, escaping its value
+func (c *Component) AddText(name, value string, params ...Param) {
+	c.Add(name, EscapeText(value), params...)
+}
+
+// Get returns the first property with the given name
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if strings.EqualFold(c.Properties[i].Name, name) {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Encode writes the component and its children as folded CRLF lines
+func (c *Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c.encode(bw)
+	return bw.Flush()
+}
+
+func (c *Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, prop := range c.Properties {
+		var sb strings.Builder
+		sb.WriteString(prop.Name)
+		for _, param := range prop.Params {
+			sb.WriteString(";")
+			sb.WriteString(param.Name)
+			sb.WriteString("=")
+			sb.WriteString(quoteParam(param.Value))
+		}
+		sb.WriteString(":")
+		sb.WriteString(prop.Value)
+		writeLine(w, sb.String())
+	}
+	for i := range c.Components {
+		c.Components[i].encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds a content line at 75 octets without splitting UTF-8 sequences
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines lose one octet to the leading space
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func quoteParam(value string) string {
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+// EscapeText escapes a TEXT value per RFC 5545 section 3.3.11
+func EscapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// UnescapeText reverses EscapeText
+func UnescapeText(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
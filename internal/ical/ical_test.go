@@ -0,0 +1,289 @@
+package ical
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	comp := &Component{Name: "VTODO"}
+	comp.AddText("SUMMARY", strings.Repeat("ä", 60))
+
+	var buf bytes.Buffer
+	if err := comp.Encode(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line exceeds %d octets: %q", maxLineOctets, line)
+		}
+		if !strings.HasPrefix(line, " ") && !strings.Contains(line, ":") {
+			t.Errorf("unexpected line: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+strings.Repeat("ä", 60)) {
+		t.Errorf("folded value does not round-trip: %q", unfolded)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	input := "Call Bob; bring notes, slides\\docs\nthen lunch"
+	escaped := EscapeText(input)
+	if escaped != `Call Bob\; bring notes\, slides\\docs\nthen lunch` {
+		t.Errorf("unexpected escaping: %s", escaped)
+	}
+	if UnescapeText(escaped) != input {
+		t.Errorf("unescape did not round-trip: %q", UnescapeText(escaped))
+	}
+}
+
+func TestFromTasks(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	tasks := []models.Task{
+		{
+			ID:            "timed",
+			Description:   "Standup",
+			Date:          json.RawMessage(`"2024-01-15"`),
+			Start:         json.RawMessage(`"09:00"`),
+			EstimatedTime: intPtr(1800),
+			Label:         strPtr("label-1"),
+			Priority:      intPtr(4),
+		},
+		{
+			ID:          "todo",
+			Description: "Pay invoice",
+			Date:        json.RawMessage(`"2024-01-15"`),
+			DueDate:     json.RawMessage(`"2024-01-20"`),
+			Complete:    true,
+			CompletedAt: json.RawMessage(`"2024-01-16T10:00:00Z"`),
+		},
+	}
+
+	cal := FromTasks(tasks, Options{
+		Location:   berlin,
+		Now:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		LabelNames: map[string]string{"label-1": "Work"},
+	})
+
+	if len(cal.Components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(cal.Components))
+	}
+
+	event := cal.Components[0]
+	if event.Name != "VEVENT" {
+		t.Errorf("expected VEVENT, got %s", event.Name)
+	}
+	expectProp(t, &event, "UID", "timed@ellieplanner.com")
+	expectProp(t, &event, "DTSTART", "20240115T080000Z")
+	expectProp(t, &event, "DTEND", "20240115T083000Z")
+	expectProp(t, &event, "CATEGORIES", "Work")
+	expectProp(t, &event, "PRIORITY", "1")
+
+	todo := cal.Components[1]
+	if todo.Name != "VTODO" {
+		t.Errorf("expected VTODO, got %s", todo.Name)
+	}
+	expectProp(t, &todo, "DTSTART", "20240115")
+	expectProp(t, &todo, "DUE", "20240120")
+	expectProp(t, &todo, "STATUS", "COMPLETED")
+	expectProp(t, &todo, "COMPLETED", "20240116T100000Z")
+}
+
+func TestFromTasks_AllDayHasNoDuration(t *testing.T) {
+	tasks := []models.Task{{
+		ID:            "allday",
+		Description:   "Write report",
+		Date:          json.RawMessage(`"2024-01-15"`),
+		EstimatedTime: intPtr(5400),
+	}}
+
+	cal := FromTasks(tasks, Options{Location: time.UTC})
+	todo := cal.Components[0]
+	expectProp(t, &todo, "DTSTART", "20240115")
+	if todo.Get("DURATION") != nil {
+		t.Errorf("expected no DURATION with a DATE start, got %s", todo.Get("DURATION").Value)
+	}
+}
+
+func TestUID_Recurring(t *testing.T) {
+	task := &models.Task{ID: "series", Recurring: true, Date: json.RawMessage(`"2024-01-15"`)}
+	if UID(task) != "series-2024-01-15@ellieplanner.com" {
+		t.Errorf("unexpected UID: %s", UID(task))
+	}
+}
+
+func expectProp(t *testing.T, comp *Component, name, value string) {
+	t.Helper()
+	prop := comp.Get(name)
+	if prop == nil {
+		t.Errorf("%s: missing property %s", comp.Name, name)
+		return
+	}
+	if prop.Value != value {
+		t.Errorf("%s: expected %s '%s', got '%s'", comp.Name, name, value, prop.Value)
+	}
+}
+
+func TestDecodeItems(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTIMEZONE\r\n" +
+		"TZID:Europe/Berlin\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:meeting-1\r\n" +
+		"SUMMARY:Prep\\, review agenda\r\n" +
+		"DTSTART;TZID=\"Europe/Berlin\":20240115T100000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20240115T104500\r\n" +
+		"CATEGORIES:Work,Meetings\r\n" +
+		"PRIORITY:1\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:todo-1\r\n" +
+		"SUMMARY:Send min\r\n" +
+		" utes\r\n" +
+		"DUE;VALUE=DATE:20240116\r\n" +
+		"STATUS:COMPLETED\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := Items(cal, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	event := items[0]
+	if event.Summary != "Prep, review agenda" {
+		t.Errorf("unexpected summary: %q", event.Summary)
+	}
+	if event.Date != "2024-01-15" || event.Start != "09:00" {
+		t.Errorf("expected 2024-01-15 09:00 UTC, got %s %s", event.Date, event.Start)
+	}
+	if event.Duration != 45*time.Minute {
+		t.Errorf("expected 45m duration, got %s", event.Duration)
+	}
+	if len(event.Categories) != 2 || event.Categories[0] != "Work" {
+		t.Errorf("unexpected categories: %v", event.Categories)
+	}
+	if event.Priority != 4 {
+		t.Errorf("expected priority 4, got %d", event.Priority)
+	}
+
+	todo := items[1]
+	if todo.Summary != "Send minutes" {
+		t.Errorf("expected unfolded summary, got %q", todo.Summary)
+	}
+	if todo.Date != "2024-01-16" || todo.Start != "" {
+		t.Errorf("expected date from DUE, got %q %q", todo.Date, todo.Start)
+	}
+	if !todo.Completed {
+		t.Error("expected completed to-do")
+	}
+}
+
+func TestItems_SkipsCancelledWithoutSummary(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:cancelled-1\r\n" +
+		"DTSTART:20240115T100000Z\r\n" +
+		"STATUS:CANCELLED\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:kept-1\r\n" +
+		"SUMMARY:Kept\r\n" +
+		"DTSTART:20240115T110000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := Items(cal, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].UID != "kept-1" {
+		t.Errorf("expected only the kept event, got %+v", items)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	tasks := []models.Task{{
+		ID:            "task-1",
+		Description:   "Focus block",
+		Date:          json.RawMessage(`"2024-03-01"`),
+		Start:         json.RawMessage(`"14:30"`),
+		EstimatedTime: intPtr(5400),
+	}}
+
+	var buf bytes.Buffer
+	if err := FromTasks(tasks, Options{Location: time.UTC}).Encode(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cal, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	items, err := Items(cal, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if items[0].UID != "task-1@ellieplanner.com" || items[0].Start != "14:30" || items[0].Duration != 90*time.Minute {
+		t.Errorf("unexpected round-trip result: %+v", items[0])
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"-PT15M":  -15 * time.Minute,
+		"P1W":     7 * 24 * time.Hour,
+	}
+	for input, expected := range tests {
+		got, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): unexpected error: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseDuration(%q): expected %s, got %s", input, expected, got)
+		}
+	}
+
+	if _, err := ParseDuration("1 hour"); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
@@ -0,0 +1,229 @@
+package ical
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Item is a to-do or event read from a calendar, with times resolved
+// into the importing user's time zone
+type Item struct {
+	UID          string        `json:"uid"`
+	RecurrenceID string        `json:"recurrence_id,omitempty"`
+	Kind         string        `json:"kind"`
+	Summary      string        `json:"summary"`
+	Date         string        `json:"date,omitempty"`
+	Start        string        `json:"start,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	Categories   []string      `json:"categories,omitempty"`
+	Priority     int           `json:"priority,omitempty"`
+	Completed    bool          `json:"completed"`
+}
+
+// Key identifies an item across imports. Overridden instances of a
+// recurring event share the UID and are told apart by RECURRENCE-ID;
+// items without a UID fall back to their summary and schedule.
+func (i *Item) Key() string {
+	if i.UID == "" {
+		return strings.Join([]string{i.Summary, i.Date, i.Start}, "|")
+	}
+	if i.RecurrenceID != "" {
+		return i.UID + "/" + i.RecurrenceID
+	}
+	return i.UID
+}
+
+// Items extracts VTODO and VEVENT entries from a calendar. Floating times
+// and unknown TZIDs are interpreted in loc.
+func Items(cal *Component, loc *time.Location) ([]Item, error) {
+	var items []Item
+
+	for i := range cal.Components {
+		comp := &cal.Components[i]
+		if comp.Name != "VTODO" && comp.Name != "VEVENT" {
+			continue
+		}
+		// Cancelled events are dropped before validation, as they often
+		// come without a SUMMARY
+		if comp.Name == "VEVENT" {
+			if status := comp.Get("STATUS"); status != nil && strings.EqualFold(status.Value, "CANCELLED") {
+				continue
+			}
+		}
+
+		item, err := itemFromComponent(comp, loc)
+		if err != nil {
+			uid := ""
+			if p := comp.Get("UID"); p != nil {
+				uid = p.Value
+			}
+			return nil, fmt.Errorf("%s %s: %w", comp.Name, uid, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func itemFromComponent(comp *Component, loc *time.Location) (Item, error) {
+	item := Item{Kind: comp.Name}
+
+	if p := comp.Get("SUMMARY"); p != nil {
+		item.Summary = strings.TrimSpace(UnescapeText(p.Value))
+	}
+	if item.Summary == "" {
+		return item, fmt.Errorf("missing SUMMARY")
+	}
+
+	if p := comp.Get("UID"); p != nil {
+		item.UID = UnescapeText(p.Value)
+	}
+	if p := comp.Get("RECURRENCE-ID"); p != nil {
+		item.RecurrenceID = p.Value
+	}
+
+	var start time.Time
+	allDay := false
+	if p := comp.Get("DTSTART"); p != nil {
+		t, isDate, err := ParseTime(p, loc)
+		if err != nil {
+			return item, fmt.Errorf("DTSTART: %w", err)
+		}
+		start, allDay = t, isDate
+		item.Date = start.Format("2006-01-02")
+		if !allDay {
+			item.Start = start.Format("15:04")
+		}
+	}
+
+	if p := comp.Get("DURATION"); p != nil {
+		d, err := ParseDuration(p.Value)
+		if err != nil {
+			return item, err
+		}
+		if !allDay {
+			item.Duration = d
+		}
+	} else if end := comp.Get("DTEND"); end != nil && comp.Name == "VEVENT" && !start.IsZero() {
+		t, _, err := ParseTime(end, loc)
+		if err != nil {
+			return item, fmt.Errorf("DTEND: %w", err)
+		}
+		if !allDay && t.After(start) {
+			item.Duration = t.Sub(start)
+		}
+	}
+
+	if item.Date == "" {
+		if p := comp.Get("DUE"); p != nil {
+			t, _, err := ParseTime(p, loc)
+			if err != nil {
+				return item, fmt.Errorf("DUE: %w", err)
+			}
+			item.Date = t.Format("2006-01-02")
+		}
+	}
+
+	for _, prop := range comp.Properties {
+		if prop.Name != "CATEGORIES" {
+			continue
+		}
+		for _, cat := range splitUnescaped(prop.Value) {
+			if cat = strings.TrimSpace(UnescapeText(cat)); cat != "" {
+				item.Categories = append(item.Categories, cat)
+			}
+		}
+	}
+
+	if p := comp.Get("PRIORITY"); p != nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(p.Value)); err == nil {
+			item.Priority = FromPriority(n)
+		}
+	}
+
+	if p := comp.Get("STATUS"); p != nil && strings.EqualFold(p.Value, "COMPLETED") {
+		item.Completed = true
+	} else if comp.Get("COMPLETED") != nil {
+		item.Completed = true
+	}
+
+	return item, nil
+}
+
+// ParseTime parses a DATE or DATE-TIME property. It reports whether the
+// value was a plain date.
+func ParseTime(prop *Property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+
+	if strings.EqualFold(prop.Param("VALUE"), "DATE") || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t.In(loc), false, nil
+	}
+
+	valueLoc := loc
+	if tzid := prop.Param("TZID"); tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			valueLoc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, valueLoc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return t.In(loc), false, nil
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseDuration parses an RFC 5545 DURATION value such as PT1H30M
+func ParseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+2])
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// splitUnescaped splits a list value on commas that are not escaped
+func splitUnescaped(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == ',' {
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
@@ -0,0 +1,195 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+const (
+	// ProdID identifies calendars generated by this CLI
+	ProdID = "-//ellie-cli//Ellie Daily Planner//EN"
+
+	uidDomain      = "ellieplanner.com"
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Options controls how tasks are converted to calendar components
+type Options struct {
+	// Location is used to interpret task start times without an offset
+	Location *time.Location
+	// Now is written as DTSTAMP
+	Now time.Time
+	// LabelNames maps label IDs to names for CATEGORIES
+	LabelNames map[string]string
+	// Todos emits every task as a VTODO, including timed ones
+	Todos bool
+	// Name is written as X-WR-CALNAME when set
+	Name string
+}
+
+// FromTasks builds a VCALENDAR. Tasks with a start time become VEVENTs,
+// all other tasks become VTODOs. Times are written in UTC so the result
+// does not depend on VTIMEZONE support in the consuming client.
+func FromTasks(tasks []models.Task, opts Options) *Component {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	cal := &Component{Name: "VCALENDAR"}
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", ProdID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	if opts.Name != "" {
+		cal.AddText("X-WR-CALNAME", opts.Name)
+	}
+
+	for i := range tasks {
+		cal.Components = append(cal.Components, taskComponent(&tasks[i], &opts))
+	}
+	return cal
+}
+
+// UID returns the stable calendar UID for a task. Recurring instances
+// share a task ID, so their date is part of the UID.
+func UID(task *models.Task) string {
+	if task.Recurring || task.RecurringID != nil {
+		if date := task.GetDateString(); date != "" {
+			return fmt.Sprintf("%s-%s@%s", task.ID, date, uidDomain)
+		}
+	}
+	return fmt.Sprintf("%s@%s", task.ID, uidDomain)
+}
+
+func taskComponent(task *models.Task, opts *Options) Component {
+	date := task.GetDateString()
+	start, timed := dateutil.ParseStart(date, task.GetStartString(), opts.Location)
+
+	name := "VTODO"
+	if timed && !opts.Todos {
+		name = "VEVENT"
+	}
+
+	comp := Component{Name: name}
+	comp.AddText("UID", UID(task))
+	comp.Add("DTSTAMP", opts.Now.UTC().Format(dateTimeFormat))
+	comp.AddText("SUMMARY", task.Description)
+
+	var duration time.Duration
+	if task.EstimatedTime != nil && *task.EstimatedTime > 0 {
+		duration = time.Duration(*task.EstimatedTime) * time.Second
+	}
+
+	due, hasDue := dateutil.ParseDay(task.GetDueDateString(), opts.Location)
+
+	switch {
+	case timed:
+		comp.Add("DTSTART", start.UTC().Format(dateTimeFormat))
+		if name == "VEVENT" && duration > 0 {
+			comp.Add("DTEND", start.Add(duration).UTC().Format(dateTimeFormat))
+		} else if duration > 0 && !hasDue {
+			comp.Add("DURATION", formatDuration(duration))
+		}
+	case date != "":
+		if day, ok := dateutil.ParseDay(date, opts.Location); ok {
+			// A DATE start would need a whole-day DURATION, which an
+			// estimate is not
+			comp.Add("DTSTART", day.Format(dateFormat), Param{Name: "VALUE", Value: "DATE"})
+		}
+	}
+
+	if name == "VTODO" {
+		if hasDue && timed {
+			// DUE must share DTSTART's value type, so use the end of the due day
+			comp.Add("DUE", due.AddDate(0, 0, 1).UTC().Format(dateTimeFormat))
+		} else if hasDue {
+			comp.Add("DUE", due.Format(dateFormat), Param{Name: "VALUE", Value: "DATE"})
+		}
+		if task.Complete {
+			comp.Add("STATUS", "COMPLETED")
+			completed := opts.Now
+			if t, err := time.Parse(time.RFC3339, task.GetCompletedAtString()); err == nil {
+				completed = t
+			}
+			comp.Add("COMPLETED", completed.UTC().Format(dateTimeFormat))
+			comp.Add("PERCENT-COMPLETE", "100")
+		} else {
+			comp.Add("STATUS", "NEEDS-ACTION")
+		}
+	} else {
+		comp.Add("STATUS", "CONFIRMED")
+		comp.Add("TRANSP", "OPAQUE")
+	}
+
+	if task.Priority != nil {
+		if p := ToPriority(*task.Priority); p > 0 {
+			comp.Add("PRIORITY", fmt.Sprintf("%d", p))
+		}
+	}
+	if task.Label != nil {
+		if labelName, ok := opts.LabelNames[*task.Label]; ok {
+			comp.AddText("CATEGORIES", labelName)
+		}
+	}
+
+	return comp
+}
+
+// ToPriority maps Ellie priorities (1 low to 4 urgent) onto the iCalendar
+// scale where 1 is highest and 9 lowest
+func ToPriority(p int) int {
+	switch p {
+	case 4:
+		return 1
+	case 3:
+		return 3
+	case 2:
+		return 5
+	case 1:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// FromPriority maps an iCalendar priority back onto the Ellie scale
+func FromPriority(p int) int {
+	switch {
+	case p <= 0:
+		return 0
+	case p <= 2:
+		return 4
+	case p <= 4:
+		return 3
+	case p == 5:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// formatDuration writes a duration as an RFC 5545 DURATION value
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+
+	s := "PT"
+	if hours > 0 {
+		s += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 {
+		s += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds > 0 || s == "PT" {
+		s += fmt.Sprintf("%dS", seconds)
+	}
+	return s
+}
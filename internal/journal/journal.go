@@ -0,0 +1,225 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/config"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+const journalFileName = "journal.json"
+
+// MaxEntries is the number of operations kept in the journal
+const MaxEntries = 100
+
+// Action describes how an entry is reverted
+type Action string
+
+const (
+	// ActionModify entries are reverted by restoring the pre-images via UpdateTask
+	ActionModify Action = "modify"
+	// ActionDelete entries are reverted by recreating the tasks via CreateTask
+	ActionDelete Action = "delete"
+)
+
+// Entry records the state of tasks before a command modified them
+type Entry struct {
+	ID      int           `json:"id"`
+	Time    time.Time     `json:"time"`
+	Command string        `json:"command"`
+	Action  Action        `json:"action"`
+	Tasks   []models.Task `json:"tasks"`
+	Undone  bool          `json:"undone,omitempty"`
+}
+
+// Path returns the location of the journal file
+func Path() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, journalFileName), nil
+}
+
+// List returns all journal entries, oldest first
+func List() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Record appends a new entry holding the pre-images of the given tasks
+func Record(command string, action Action, tasks []models.Task) (*Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	id := 1
+	if len(entries) > 0 {
+		id = entries[len(entries)-1].ID + 1
+	}
+
+	entry := Entry{
+		ID:      id,
+		Time:    time.Now(),
+		Command: command,
+		Action:  action,
+		Tasks:   tasks,
+	}
+	entries = append(entries, entry)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+
+	if err := save(entries); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Find returns the entry with the given ID, or the most recent entry that
+// has not been undone yet when id is zero
+func Find(id int) (*Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if id == 0 && !entries[i].Undone {
+			return &entries[i], nil
+		}
+		if id != 0 && entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+
+	if id == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	return nil, fmt.Errorf("journal entry %d not found", id)
+}
+
+// MarkUndone flags an entry as reverted
+func MarkUndone(id int) error {
+	entries, err := List()
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if entries[i].ID == id {
+			entries[i].Undone = true
+			return save(entries)
+		}
+	}
+	return fmt.Errorf("journal entry %d not found", id)
+}
+
+func save(entries []Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// RestoreRequest builds an update that puts a task back into its recorded
+// state. Every field is sent; fields that were unset are sent empty or
+// zero so that values added since are cleared.
+func RestoreRequest(task *models.Task) *models.UpdateTaskRequest {
+	desc := task.Description
+	date := task.GetDateString()
+	start := task.GetStartString()
+	due := task.GetDueDateString()
+	complete := task.Complete
+
+	return &models.UpdateTaskRequest{
+		Description:   &desc,
+		Date:          &date,
+		Start:         &start,
+		DueDate:       &due,
+		EstimatedTime: intOrZero(task.EstimatedTime),
+		ActualTime:    intOrZero(task.ActualTime),
+		Complete:      &complete,
+		ListID:        stringOrEmpty(task.ListID),
+		Label:         stringOrEmpty(task.Label),
+		Priority:      intOrZero(task.Priority),
+	}
+}
+
+func intOrZero(v *int) *int {
+	n := 0
+	if v != nil {
+		n = *v
+	}
+	return &n
+}
+
+func stringOrEmpty(v *string) *string {
+	s := ""
+	if v != nil {
+		s = *v
+	}
+	return &s
+}
+
+// RecreateRequest builds a create request for a task that was deleted
+func RecreateRequest(task *models.Task) *models.CreateTaskRequest {
+	req := &models.CreateTaskRequest{
+		Description:   task.Description,
+		EstimatedTime: task.EstimatedTime,
+		ListID:        task.ListID,
+		Label:         task.Label,
+		Priority:      task.Priority,
+	}
+
+	if date := task.GetDateString(); date != "" {
+		req.Date = &date
+	}
+	if start := task.GetStartString(); start != "" {
+		req.Start = &start
+	}
+	if due := task.GetDueDateString(); due != "" {
+		req.DueDate = &due
+	}
+	return req
+}
@@ -0,0 +1,155 @@
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func setupJournalDir(t *testing.T) {
+	originalHome := os.Getenv("HOME")
+	originalXDG := os.Getenv("XDG_CONFIG_HOME")
+
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+		if originalXDG != "" {
+			os.Setenv("XDG_CONFIG_HOME", originalXDG)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	})
+
+	// Use temp dir for config to work in sandboxed environments
+	tmpDir := t.TempDir()
+	os.Setenv("HOME", tmpDir)
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+}
+
+func TestRecordAndFind(t *testing.T) {
+	setupJournalDir(t)
+
+	first, err := Record("tasks update", ActionModify, []models.Task{{ID: "task-1", Description: "First"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := Record("tasks delete", ActionDelete, []models.Task{{ID: "task-2", Description: "Second"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("expected IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+
+	entry, err := Find(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.ID != 2 {
+		t.Errorf("expected latest entry 2, got %d", entry.ID)
+	}
+
+	if err := MarkUndone(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, err = Find(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.ID != 1 {
+		t.Errorf("expected entry 1 after undoing 2, got %d", entry.ID)
+	}
+	if entry.Tasks[0].ID != "task-1" {
+		t.Errorf("expected task 'task-1', got '%s'", entry.Tasks[0].ID)
+	}
+}
+
+func TestFind_Empty(t *testing.T) {
+	setupJournalDir(t)
+
+	if _, err := Find(0); err == nil {
+		t.Error("expected error for empty journal")
+	}
+}
+
+func TestRecord_Trims(t *testing.T) {
+	setupJournalDir(t)
+
+	for i := 0; i < MaxEntries+5; i++ {
+		if _, err := Record("tasks complete", ActionModify, []models.Task{{ID: "task"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	entries, err := List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != MaxEntries {
+		t.Errorf("expected %d entries, got %d", MaxEntries, len(entries))
+	}
+	if entries[len(entries)-1].ID != MaxEntries+5 {
+		t.Errorf("expected last ID %d, got %d", MaxEntries+5, entries[len(entries)-1].ID)
+	}
+}
+
+func TestRestoreRequest(t *testing.T) {
+	priority := 3
+	task := &models.Task{
+		ID:          "task-1",
+		Description: "Write report",
+		Date:        json.RawMessage(`"2024-01-15"`),
+		Complete:    false,
+		Priority:    &priority,
+	}
+
+	req := RestoreRequest(task)
+	if *req.Description != "Write report" {
+		t.Errorf("expected description 'Write report', got '%s'", *req.Description)
+	}
+	if *req.Date != "2024-01-15" {
+		t.Errorf("expected date '2024-01-15', got '%s'", *req.Date)
+	}
+	if *req.Complete {
+		t.Error("expected complete to be restored as false")
+	}
+	if *req.Priority != 3 {
+		t.Errorf("expected priority 3, got %d", *req.Priority)
+	}
+}
+
+func TestRestoreRequestClearsUnsetFields(t *testing.T) {
+	// The task had no label, list, priority or estimate before a change
+	// that set them; undoing it must send them empty
+	task := &models.Task{ID: "task-1", Description: "Write report"}
+
+	req := RestoreRequest(task)
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]interface{}{
+		"label":          "",
+		"listId":         "",
+		"priority":       0.0,
+		"estimated_time": 0.0,
+		"actual_time":    0.0,
+	} {
+		got, ok := fields[key]
+		if !ok {
+			t.Errorf("expected %s to be sent, got %s", key, data)
+			continue
+		}
+		if got != want {
+			t.Errorf("expected %s to be %v, got %v", key, want, got)
+		}
+	}
+}
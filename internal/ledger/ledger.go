@@ -0,0 +1,88 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/config"
+)
+
+const ledgerDirName = "imports"
+
+// Record links an external item to the task created for it
+type Record struct {
+	TaskID     string    `json:"task_id"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// Ledger remembers which external items were already imported so that
+// re-running an import does not create duplicates
+type Ledger struct {
+	path    string
+	Records map[string]Record `json:"records"`
+}
+
+// Open loads the ledger with the given name, creating an empty one if it
+// does not exist yet
+func Open(name string) (*Ledger, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Ledger{
+		path:    filepath.Join(configDir, ledgerDirName, name+".json"),
+		Records: make(map[string]Record),
+	}
+
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("failed to parse import ledger: %w", err)
+	}
+	if l.Records == nil {
+		l.Records = make(map[string]Record)
+	}
+	return l, nil
+}
+
+// Get returns the record for an external key
+func (l *Ledger) Get(key string) (Record, bool) {
+	r, ok := l.Records[key]
+	return r, ok
+}
+
+// Put remembers that key was imported as taskID
+func (l *Ledger) Put(key, taskID string) {
+	l.Records[key] = Record{TaskID: taskID, ImportedAt: time.Now().UTC()}
+}
+
+// Save writes the ledger back to disk
+func (l *Ledger) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := l.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	return nil
+}
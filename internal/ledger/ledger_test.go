@@ -0,0 +1,49 @@
+package ledger
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLedger_PersistsRecords(t *testing.T) {
+	originalHome := os.Getenv("HOME")
+	originalXDG := os.Getenv("XDG_CONFIG_HOME")
+	defer func() {
+		os.Setenv("HOME", originalHome)
+		if originalXDG != "" {
+			os.Setenv("XDG_CONFIG_HOME", originalXDG)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	}()
+
+	// Use temp dir for config to work in sandboxed environments
+	tmpDir := t.TempDir()
+	os.Setenv("HOME", tmpDir)
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	book, err := Open("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := book.Get("uid-1"); ok {
+		t.Fatal("expected empty ledger")
+	}
+
+	book.Put("uid-1", "task-1")
+	if err := book.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened, err := Open("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	record, ok := reopened.Get("uid-1")
+	if !ok {
+		t.Fatal("expected record after reopening")
+	}
+	if record.TaskID != "task-1" {
+		t.Errorf("expected task 'task-1', got '%s'", record.TaskID)
+	}
+}
@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+const (
+	// BeginMarker and EndMarker delimit the generated block inside a note
+	BeginMarker = "<!-- ellie:begin -->"
+	EndMarker   = "<!-- ellie:end -->"
+
+	idPrefix = "<!-- ellie:"
+	idSuffix = " -->"
+)
+
+// DefaultTemplate renders a heading followed by the checklist
+const DefaultTemplate = `## Agenda {{ .Date }}
+
+{{ range .Items }}{{ .Line }}
+{{ else }}_No tasks_
+{{ end }}`
+
+// Item is a task prepared for a template
+type Item struct {
+	Task     *models.Task
+	Done     bool
+	Time     string
+	Estimate string
+	Tags     []string
+	// Line is the rendered checklist entry including nested subtasks
+	Line string
+}
+
+// Data is passed to the note template
+type Data struct {
+	Date  string
+	Items []Item
+}
+
+// Options controls rendering
+type Options struct {
+	Location   *time.Location
+	LabelNames map[string]string
+	// Template overrides DefaultTemplate
+	Template string
+}
+
+// Render formats the agenda for date as a Markdown checklist. Timed tasks
+// come first in start order, followed by the rest in their given order.
+func Render(date string, tasks []models.Task, opts Options) (string, error) {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+
+	tmpl, err := template.New("note").Parse(opts.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	sorted := append([]models.Task(nil), tasks...)
+	planner.SortByStart(sorted, opts.Location)
+
+	data := Data{Date: date}
+	for i := range sorted {
+		data.Items = append(data.Items, newItem(&sorted[i], &opts))
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+func newItem(task *models.Task, opts *Options) Item {
+	item := Item{Task: task, Done: task.Complete}
+
+	if start, ok := dateutil.ParseStart(task.GetDateString(), task.GetStartString(), opts.Location); ok {
+		item.Time = start.Format("15:04")
+	}
+	if task.EstimatedTime != nil && *task.EstimatedTime >= 60 {
+		item.Estimate = dateutil.FormatMinutes(*task.EstimatedTime / 60)
+	}
+	if task.Label != nil {
+		if name, ok := opts.LabelNames[*task.Label]; ok {
+			item.Tags = append(item.Tags, "#"+strings.Join(strings.Fields(name), "-"))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(checkbox(task.Complete))
+	if item.Time != "" {
+		sb.WriteString(item.Time + " ")
+	}
+	sb.WriteString(oneLine(task.Description))
+	if item.Estimate != "" {
+		sb.WriteString(" (" + item.Estimate + ")")
+	}
+	for _, tag := range item.Tags {
+		sb.WriteString(" " + tag)
+	}
+	sb.WriteString(" " + idComment(task))
+
+	subtasks := append([]models.Subtask(nil), task.Subtasks...)
+	sort.SliceStable(subtasks, func(i, j int) bool {
+		return sortOrder(subtasks[i]) < sortOrder(subtasks[j])
+	})
+	for _, sub := range subtasks {
+		sb.WriteString("\n    " + checkbox(sub.Completed) + oneLine(sub.Description))
+	}
+
+	item.Line = sb.String()
+	return item
+}
+
+// Merge replaces the generated block between the markers in note, keeping
+// everything else untouched. Without markers the block is appended.
+func Merge(note, block string) string {
+	wrapped := BeginMarker + "\n" + strings.TrimRight(block, "\n") + "\n" + EndMarker
+
+	begin := strings.Index(note, BeginMarker)
+	end := strings.Index(note, EndMarker)
+	if begin >= 0 && end > begin {
+		return note[:begin] + wrapped + note[end+len(EndMarker):]
+	}
+
+	if note == "" {
+		return wrapped + "\n"
+	}
+	if !strings.HasSuffix(note, "\n") {
+		note += "\n"
+	}
+	return note + "\n" + wrapped + "\n"
+}
+
+func checkbox(done bool) string {
+	if done {
+		return "- [x] "
+	}
+	return "- [ ] "
+}
+
+// idComment marks a line with the task ID and the state it was exported
+// in, e.g. "<!-- ellie:ID x 1a2b3c4d -->", so that sync only pushes what
+// was edited in the note. The state is the checkbox and a hash of the
+// description as Parse will read it back.
+func idComment(task *models.Task) string {
+	done := "-"
+	if task.Complete {
+		done = "x"
+	}
+	return idPrefix + task.ID + " " + done + " " + descriptionHash(stripDecorations(oneLine(task.Description))) + idSuffix
+}
+
+func descriptionHash(desc string) string {
+	sum := sha256.Sum256([]byte(desc))
+	return hex.EncodeToString(sum[:4])
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func sortOrder(sub models.Subtask) int {
+	if sub.SortOrder == nil {
+		return 0
+	}
+	return *sub.SortOrder
+}
@@ -0,0 +1,172 @@
+package markdown
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRender(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "untimed", Description: "Inbox zero", Complete: true},
+		{
+			ID:            "timed",
+			Description:   "Write  report",
+			Date:          json.RawMessage(`"2024-01-15"`),
+			Start:         json.RawMessage(`"09:00"`),
+			EstimatedTime: intPtr(5400),
+			Label:         strPtr("label-1"),
+			Subtasks: []models.Subtask{
+				{ID: "s2", Description: "Send", SortOrder: intPtr(2)},
+				{ID: "s1", Description: "Outline", Completed: true, SortOrder: intPtr(1)},
+			},
+		},
+	}
+
+	out, err := Render("2024-01-15", tasks, Options{
+		Location:   time.UTC,
+		LabelNames: map[string]string{"label-1": "Deep Work"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "## Agenda 2024-01-15\n\n" +
+		"- [ ] 09:00 Write report (1h30m) #Deep-Work <!-- ellie:timed - 316f0d30 -->\n" +
+		"    - [x] Outline\n" +
+		"    - [ ] Send\n" +
+		"- [x] Inbox zero <!-- ellie:untimed x a5a38b88 -->\n"
+	if out != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out, expected)
+	}
+}
+
+func TestRender_CustomTemplate(t *testing.T) {
+	tasks := []models.Task{{ID: "t1", Description: "Call", Complete: true}}
+
+	out, err := Render("2024-01-15", tasks, Options{
+		Template: "{{ range .Items }}{{ if .Done }}done: {{ .Task.Description }}{{ end }}{{ end }}",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "done: Call" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	if _, err := Render("2024-01-15", tasks, Options{Template: "{{ .Broken"}); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	note := "# Monday\n\nThoughts before.\n\n" + BeginMarker + "\nold agenda\n" + EndMarker + "\n\nThoughts after.\n"
+
+	merged := Merge(note, "new agenda\n")
+	if !strings.Contains(merged, BeginMarker+"\nnew agenda\n"+EndMarker) {
+		t.Errorf("block not replaced:\n%s", merged)
+	}
+	if strings.Contains(merged, "old agenda") {
+		t.Error("old block still present")
+	}
+	if !strings.HasPrefix(merged, "# Monday\n\nThoughts before.") || !strings.HasSuffix(merged, "Thoughts after.\n") {
+		t.Errorf("user content was modified:\n%s", merged)
+	}
+
+	appended := Merge("# Notes", "agenda")
+	if appended != "# Notes\n\n"+BeginMarker+"\nagenda\n"+EndMarker+"\n" {
+		t.Errorf("unexpected append result: %q", appended)
+	}
+}
+
+func TestParseAndDiff(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "a", Description: "Write report", Date: json.RawMessage(`"2024-01-15"`), Start: json.RawMessage(`"09:00"`), EstimatedTime: intPtr(1800), Label: strPtr("l")},
+		{ID: "b", Description: "Call (back) Bob", Complete: true},
+		{ID: "c", Description: "Unchanged"},
+	}
+
+	out, err := Render("2024-01-15", tasks, Options{Location: time.UTC, LabelNames: map[string]string{"l": "Work"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	edited := strings.Replace(out, "- [ ] 09:00 Write report", "- [x] 09:00 Write final report", 1)
+	edited = strings.Replace(edited, "- [x] Call (back) Bob", "- [ ] Call (back) Bob", 1)
+
+	entries, err := Parse(strings.NewReader("Some prose\n" + edited))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Description != "Write final report" || entries[0].Line != 4 {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+
+	byID := make(map[string]*models.Task)
+	for i := range tasks {
+		byID[tasks[i].ID] = &tasks[i]
+	}
+
+	changes := Diff(entries, byID)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+
+	if changes[0].Task.ID != "a" || changes[0].Complete == nil || !*changes[0].Complete {
+		t.Errorf("expected task a to be completed: %+v", changes[0])
+	}
+	if changes[0].Description == nil || *changes[0].Description != "Write final report" {
+		t.Errorf("expected description edit for task a: %+v", changes[0])
+	}
+	if changes[1].Task.ID != "b" || changes[1].Complete == nil || *changes[1].Complete || changes[1].Description != nil {
+		t.Errorf("expected task b to be reopened only: %+v", changes[1])
+	}
+}
+
+func TestDiff_IgnoresChangesMadeInTheApp(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "a", Description: "Write report"},
+		{ID: "b", Description: "Call Bob"},
+	}
+	out, err := Render("2024-01-15", tasks, Options{Location: time.UTC})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// After the export, a is completed and b renamed in the app
+	tasks[0].Complete = true
+	tasks[1].Description = "Call Bob back"
+	byID := map[string]*models.Task{"a": &tasks[0], "b": &tasks[1]}
+
+	if changes := Diff(entries, byID); len(changes) != 0 {
+		t.Errorf("expected the unedited note to change nothing, got %+v", changes)
+	}
+
+	// Lines without a recorded state are compared with the task
+	legacy, err := Parse(strings.NewReader("- [ ] Write report <!-- ellie:a -->\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changes := Diff(legacy, byID); len(changes) != 1 || changes[0].Complete == nil || *changes[0].Complete {
+		t.Errorf("expected the legacy line to reopen a, got %+v", changes)
+	}
+}
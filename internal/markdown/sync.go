@@ -0,0 +1,122 @@
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+var (
+	checklistPattern = regexp.MustCompile(`^\s*[-*+] \[([ xX])\] (.*?)\s*<!-- ellie:(\S+)(?: ([x-]) ([0-9a-f]+))? -->\s*$`)
+	timePattern      = regexp.MustCompile(`^\d{1,2}:\d{2}\s+`)
+	tagsPattern      = regexp.MustCompile(`(\s+#[^\s#]+)+$`)
+	estimatePattern  = regexp.MustCompile(`\s+\((\d+h)?(\d+m)?\)$`)
+)
+
+// Entry is a checklist line that carries a task ID. Lines written by
+// Render also record the checkbox and description hash at export; notes
+// from older versions have no Exported state.
+type Entry struct {
+	Line        int          `json:"line"`
+	TaskID      string       `json:"task_id"`
+	Done        bool         `json:"done"`
+	Description string       `json:"description"`
+	Exported    *ExportState `json:"exported,omitempty"`
+}
+
+// ExportState is a task's state when the note was written
+type ExportState struct {
+	Done            bool   `json:"done"`
+	DescriptionHash string `json:"description_hash"`
+}
+
+// Parse extracts the checklist lines written by Render. The start time,
+// estimate and tags around the description are stripped again.
+func Parse(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		m := checklistPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		entry := Entry{
+			Line:        lineNo,
+			TaskID:      m[3],
+			Done:        m[1] != " ",
+			Description: stripDecorations(m[2]),
+		}
+		if m[4] != "" {
+			entry.Exported = &ExportState{Done: m[4] == "x", DescriptionHash: m[5]}
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read note: %w", err)
+	}
+	return entries, nil
+}
+
+// Change is an edit made in the note that differs from the task
+type Change struct {
+	Entry       Entry        `json:"entry"`
+	Task        *models.Task `json:"task"`
+	Complete    *bool        `json:"complete,omitempty"`
+	Description *string      `json:"description,omitempty"`
+}
+
+// Diff finds the edits made in the note. Entries with an exported state
+// are compared with it, so that changes made in the app since the export
+// are not overwritten by stale lines; others are compared with the current
+// task. Edits that match the task already and entries whose task is
+// missing from tasks are ignored.
+func Diff(entries []Entry, tasks map[string]*models.Task) []Change {
+	var changes []Change
+
+	for _, entry := range entries {
+		task, ok := tasks[entry.TaskID]
+		if !ok {
+			continue
+		}
+
+		change := Change{Entry: entry, Task: task}
+		toggled := entry.Done != task.Complete
+		// Compare against the description as it would have been rendered
+		// and parsed, so decorations inside it do not look like edits
+		edited := entry.Description != "" && entry.Description != stripDecorations(oneLine(task.Description))
+		if entry.Exported != nil {
+			toggled = toggled && entry.Done != entry.Exported.Done
+			edited = edited && descriptionHash(entry.Description) != entry.Exported.DescriptionHash
+		}
+
+		if toggled {
+			done := entry.Done
+			change.Complete = &done
+		}
+		if edited {
+			desc := entry.Description
+			change.Description = &desc
+		}
+
+		if change.Complete != nil || change.Description != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func stripDecorations(s string) string {
+	s = strings.TrimSpace(s)
+	s = timePattern.ReplaceAllString(s, "")
+	s = tagsPattern.ReplaceAllString(s, "")
+	s = estimatePattern.ReplaceAllString(s, "")
+	return strings.TrimSpace(s)
+}
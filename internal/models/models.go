@@ -19,6 +19,7 @@ type Task struct {
 	RecurringID   *string         `json:"recurring_id,omitempty"`
 	Recurring     bool            `json:"recurring"`
 	CreatedAt     json.RawMessage `json:"created_at,omitempty"`
+	Subtasks      []Subtask       `json:"subtasks,omitempty"`
 }
 
 // GetDateString attempts to extract a date string from the Date field
@@ -45,6 +46,42 @@ func (t *Task) GetStartString() string {
 	return ""
 }
 
+// GetDueDateString attempts to extract a due date string
+func (t *Task) GetDueDateString() string {
+	if t.DueDate == nil || string(t.DueDate) == "null" {
+		return ""
+	}
+	var dueStr string
+	if json.Unmarshal(t.DueDate, &dueStr) == nil {
+		return dueStr
+	}
+	return ""
+}
+
+// GetCompletedAtString attempts to extract the completion timestamp
+func (t *Task) GetCompletedAtString() string {
+	if t.CompletedAt == nil || string(t.CompletedAt) == "null" {
+		return ""
+	}
+	var completedStr string
+	if json.Unmarshal(t.CompletedAt, &completedStr) == nil {
+		return completedStr
+	}
+	return ""
+}
+
+// GetCreatedAtString attempts to extract the creation timestamp
+func (t *Task) GetCreatedAtString() string {
+	if t.CreatedAt == nil || string(t.CreatedAt) == "null" {
+		return ""
+	}
+	var createdStr string
+	if json.Unmarshal(t.CreatedAt, &createdStr) == nil {
+		return createdStr
+	}
+	return ""
+}
+
 // Subtask represents a subtask within a task
 type Subtask struct {
 	ID          string `json:"id"`
@@ -53,6 +90,22 @@ type Subtask struct {
 	SortOrder   *int   `json:"sortOrder,omitempty"`
 }
 
+// RecurringTask represents a series of recurring tasks. Rule is an
+// RRULE such as "FREQ=WEEKLY;BYDAY=MO".
+type RecurringTask struct {
+	ID            string   `json:"id"`
+	Description   string   `json:"description"`
+	Rule          string   `json:"rrule"`
+	StartDate     string   `json:"start_date"`
+	EndDate       *string  `json:"end_date,omitempty"`
+	Start         *string  `json:"start,omitempty"`
+	EstimatedTime *int     `json:"estimated_time,omitempty"`
+	ListID        *string  `json:"listId,omitempty"`
+	Label         *string  `json:"label,omitempty"`
+	Priority      *int     `json:"priority,omitempty"`
+	SkippedDates  []string `json:"skipped_dates,omitempty"`
+}
+
 // Label represents a label for categorizing tasks
 type Label struct {
 	ID    string `json:"id"`
@@ -101,6 +154,7 @@ type CreateTaskRequest struct {
 	Description   string  `json:"description"`
 	Date          *string `json:"date,omitempty"`
 	Start         *string `json:"start,omitempty"`
+	DueDate       *string `json:"due_date,omitempty"`
 	EstimatedTime *int    `json:"estimated_time,omitempty"`
 	ListID        *string `json:"listId,omitempty"`
 	Label         *string `json:"label,omitempty"`
@@ -112,7 +166,9 @@ type UpdateTaskRequest struct {
 	Description   *string `json:"description,omitempty"`
 	Date          *string `json:"date,omitempty"`
 	Start         *string `json:"start,omitempty"`
+	DueDate       *string `json:"due_date,omitempty"`
 	EstimatedTime *int    `json:"estimated_time,omitempty"`
+	ActualTime    *int    `json:"actual_time,omitempty"`
 	Complete      *bool   `json:"complete,omitempty"`
 	ListID        *string `json:"listId,omitempty"`
 	Label         *string `json:"label,omitempty"`
@@ -135,8 +191,78 @@ type DeleteTaskRequest struct {
 	TaskID string `json:"taskId"`
 }
 
+// CreateRecurringTaskRequest represents the request body for creating a recurring task series
+type CreateRecurringTaskRequest struct {
+	Description   string  `json:"description"`
+	Rule          string  `json:"rrule"`
+	StartDate     string  `json:"start_date"`
+	Start         *string `json:"start,omitempty"`
+	EstimatedTime *int    `json:"estimated_time,omitempty"`
+	ListID        *string `json:"listId,omitempty"`
+	Label         *string `json:"label,omitempty"`
+	Priority      *int    `json:"priority,omitempty"`
+}
+
+// RecurringOccurrenceRequest represents the request body for skipping or
+// rescheduling a single occurrence of a recurring task
+type RecurringOccurrenceRequest struct {
+	RecurringID string  `json:"recurringId"`
+	Date        string  `json:"date"`
+	NewDate     *string `json:"new_date,omitempty"`
+	Start       *string `json:"start,omitempty"`
+}
+
+// StopRecurringTaskRequest represents the request body for ending a recurring task series
+type StopRecurringTaskRequest struct {
+	RecurringID string `json:"recurringId"`
+	EndDate     string `json:"end_date"`
+}
+
 // APIError represents an error response from the API
 type APIError struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
 }
+
+// PriorityLetter maps an Ellie priority onto the A-D letters used by
+// plain-text formats, where A is the most important
+func PriorityLetter(p int) string {
+	switch p {
+	case 4:
+		return "A"
+	case 3:
+		return "B"
+	case 2:
+		return "C"
+	case 1:
+		return "D"
+	default:
+		return ""
+	}
+}
+
+// PriorityFromLetter is the inverse of PriorityLetter. Letters after D
+// map to the lowest priority.
+func PriorityFromLetter(letter string) int {
+	if len(letter) != 1 {
+		return 0
+	}
+	switch c := letter[0] &^ 0x20; {
+	case c == 'A':
+		return 4
+	case c == 'B':
+		return 3
+	case c == 'C':
+		return 2
+	case c >= 'D' && c <= 'Z':
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Names maps list and label IDs to their display names
+type Names struct {
+	Lists  map[string]string
+	Labels map[string]string
+}
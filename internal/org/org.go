@@ -0,0 +1,238 @@
+package org
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PropertyID is the drawer property linking a heading to an Ellie task
+const PropertyID = "ELLIE_ID"
+
+var (
+	headingPattern   = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	priorityPattern  = regexp.MustCompile(`^\[#([A-Za-z])\]\s*`)
+	tagsPattern      = regexp.MustCompile(`\s+(:(?:[^\s:]+:)+)\s*$`)
+	timestampPattern = regexp.MustCompile(`[<\[](\d{4}-\d{2}-\d{2})(?:\s+[^\s\d>\]]+)?(?:\s+(\d{1,2}:\d{2})(?:-(\d{1,2}:\d{2}))?)?[^>\]]*[>\]]`)
+	planningPattern  = regexp.MustCompile(`(SCHEDULED|DEADLINE|CLOSED):\s*([<\[][^>\]]*[>\]])`)
+	propertyPattern  = regexp.MustCompile(`^\s*:([^:\s]+):\s*(.*?)\s*$`)
+)
+
+// Timestamp is an org timestamp with an optional time range
+type Timestamp struct {
+	Date  string `json:"date"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// String formats an active timestamp such as <2024-01-15 Mon 09:00-09:30>
+func (ts Timestamp) String() string {
+	return ts.format('<', '>')
+}
+
+func (ts Timestamp) format(open, close byte) string {
+	s := string(open) + ts.Date
+	if t, err := time.Parse("2006-01-02", ts.Date); err == nil {
+		s += " " + t.Format("Mon")
+	}
+	if ts.Start != "" {
+		s += " " + ts.Start
+		if ts.End != "" {
+			s += "-" + ts.End
+		}
+	}
+	return s + string(close)
+}
+
+// Heading is a TODO or DONE entry
+type Heading struct {
+	Level      int               `json:"level"`
+	Line       int               `json:"line"`
+	Done       bool              `json:"done"`
+	Priority   string            `json:"priority,omitempty"`
+	Title      string            `json:"title"`
+	Tags       []string          `json:"tags,omitempty"`
+	Scheduled  *Timestamp        `json:"scheduled,omitempty"`
+	Deadline   *Timestamp        `json:"deadline,omitempty"`
+	Closed     *Timestamp        `json:"closed,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// ID returns the linked task ID, if any
+func (h *Heading) ID() string {
+	return h.Properties[PropertyID]
+}
+
+// String formats the heading with its planning line and property drawer
+func (h *Heading) String() string {
+	var sb strings.Builder
+
+	level := h.Level
+	if level < 1 {
+		level = 1
+	}
+	sb.WriteString(strings.Repeat("*", level))
+	if h.Done {
+		sb.WriteString(" DONE")
+	} else {
+		sb.WriteString(" TODO")
+	}
+	if h.Priority != "" {
+		sb.WriteString(" [#" + h.Priority + "]")
+	}
+	sb.WriteString(" " + h.Title)
+	if len(h.Tags) > 0 {
+		sb.WriteString(" :" + strings.Join(h.Tags, ":") + ":")
+	}
+	sb.WriteString("\n")
+
+	indent := strings.Repeat(" ", level+1)
+	var planning []string
+	if h.Closed != nil {
+		planning = append(planning, "CLOSED: "+h.Closed.format('[', ']'))
+	}
+	if h.Scheduled != nil {
+		planning = append(planning, "SCHEDULED: "+h.Scheduled.String())
+	}
+	if h.Deadline != nil {
+		planning = append(planning, "DEADLINE: "+h.Deadline.String())
+	}
+	if len(planning) > 0 {
+		sb.WriteString(indent + strings.Join(planning, " ") + "\n")
+	}
+
+	if len(h.Properties) > 0 {
+		sb.WriteString(indent + ":PROPERTIES:\n")
+		for _, key := range propertyOrder(h.Properties) {
+			sb.WriteString(fmt.Sprintf("%s:%s: %s\n", indent, key, h.Properties[key]))
+		}
+		sb.WriteString(indent + ":END:\n")
+	}
+
+	return sb.String()
+}
+
+// Write formats headings one after another
+func Write(w io.Writer, headings []Heading) error {
+	bw := bufio.NewWriter(w)
+	for i := range headings {
+		bw.WriteString(headings[i].String())
+	}
+	return bw.Flush()
+}
+
+// Parse reads every TODO and DONE heading with its planning line and
+// property drawer. Other headings and body text are ignored.
+func Parse(r io.Reader) ([]Heading, error) {
+	var headings []Heading
+	var current *Heading
+	inDrawer := false
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			inDrawer = false
+			current = nil
+			if h, ok := parseHeading(len(m[1]), m[2]); ok {
+				h.Line = lineNo
+				headings = append(headings, h)
+				current = &headings[len(headings)-1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.EqualFold(trimmed, ":PROPERTIES:"):
+			inDrawer = true
+		case inDrawer && strings.EqualFold(trimmed, ":END:"):
+			inDrawer = false
+		case inDrawer:
+			if m := propertyPattern.FindStringSubmatch(line); m != nil {
+				current.Properties[strings.ToUpper(m[1])] = m[2]
+			}
+		default:
+			for _, m := range planningPattern.FindAllStringSubmatch(line, -1) {
+				ts := parseTimestamp(m[2])
+				if ts == nil {
+					continue
+				}
+				switch m[1] {
+				case "SCHEDULED":
+					current.Scheduled = ts
+				case "DEADLINE":
+					current.Deadline = ts
+				case "CLOSED":
+					current.Closed = ts
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read org file: %w", err)
+	}
+	return headings, nil
+}
+
+func parseHeading(level int, rest string) (Heading, bool) {
+	h := Heading{Level: level, Properties: make(map[string]string)}
+
+	keyword, title, _ := strings.Cut(rest, " ")
+	switch keyword {
+	case "TODO":
+	case "DONE":
+		h.Done = true
+	default:
+		return h, false
+	}
+
+	if m := priorityPattern.FindStringSubmatch(title); m != nil {
+		h.Priority = strings.ToUpper(m[1])
+		title = title[len(m[0]):]
+	}
+	if m := tagsPattern.FindStringSubmatch(title); m != nil {
+		for _, tag := range strings.Split(strings.Trim(m[1], ":"), ":") {
+			if tag != "" {
+				h.Tags = append(h.Tags, tag)
+			}
+		}
+		title = title[:len(title)-len(m[0])]
+	}
+	h.Title = strings.TrimSpace(title)
+	return h, h.Title != ""
+}
+
+func parseTimestamp(s string) *Timestamp {
+	m := timestampPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	return &Timestamp{Date: m[1], Start: m[2], End: m[3]}
+}
+
+// propertyOrder puts the Ellie ID first and the rest alphabetically
+func propertyOrder(props map[string]string) []string {
+	var keys []string
+	if _, ok := props[PropertyID]; ok {
+		keys = append(keys, PropertyID)
+	}
+	var rest []string
+	for key := range props {
+		if key != PropertyID {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
@@ -0,0 +1,134 @@
+package org
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var testNames = models.Names{
+	Lists:  map[string]string{"list-1": "Clients"},
+	Labels: map[string]string{"label-1": "Deep Work"},
+}
+
+func TestFromTask(t *testing.T) {
+	task := &models.Task{
+		ID:            "task-1",
+		Description:   "Write report",
+		Date:          json.RawMessage(`"2024-01-15"`),
+		Start:         json.RawMessage(`"09:00"`),
+		DueDate:       json.RawMessage(`"2024-01-20"`),
+		EstimatedTime: intPtr(1800),
+		Priority:      intPtr(4),
+		Label:         strPtr("label-1"),
+		ListID:        strPtr("list-1"),
+	}
+
+	h := FromTask(task, testNames, time.UTC)
+	expected := "* TODO [#A] Write report :Deep_Work:\n" +
+		"  SCHEDULED: <2024-01-15 Mon 09:00-09:30> DEADLINE: <2024-01-20 Sat>\n" +
+		"  :PROPERTIES:\n" +
+		"  :ELLIE_ID: task-1\n" +
+		"  :ELLIE_LIST: Clients\n" +
+		"  :Effort: 0:30\n" +
+		"  :END:\n"
+	if h.String() != expected {
+		t.Errorf("unexpected heading:\n%s\nexpected:\n%s", h.String(), expected)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	task := &models.Task{
+		ID:            "task-1",
+		Description:   "Write report",
+		Date:          json.RawMessage(`"2024-01-15"`),
+		Start:         json.RawMessage(`"09:00"`),
+		EstimatedTime: intPtr(1800),
+		Priority:      intPtr(3),
+		Label:         strPtr("label-1"),
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []Heading{FromTask(task, testNames, time.UTC)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headings, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(headings) != 1 {
+		t.Fatalf("expected 1 heading, got %d", len(headings))
+	}
+
+	h := &headings[0]
+	if h.ID() != "task-1" {
+		t.Errorf("expected ID task-1, got %q", h.ID())
+	}
+	if req, _ := h.UpdateRequest(task, testNames, time.UTC); req != nil {
+		t.Errorf("expected no changes after round-trip, got %+v", req)
+	}
+
+	h.Done = true
+	h.Scheduled.Start = "10:00"
+	req, _ := h.UpdateRequest(task, testNames, time.UTC)
+	if req == nil || req.Complete == nil || !*req.Complete || req.Start == nil || *req.Start != "10:00" {
+		t.Errorf("expected completion and start change, got %+v", req)
+	}
+	if req.Description != nil || req.Priority != nil || req.Label != nil {
+		t.Errorf("expected only changed fields, got %+v", req)
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := `#+TITLE: Work
+* Projects
+** TODO [#B] Review PR    :code:review:
+   DEADLINE: <2024-02-01 Thu>
+Some notes about the review.
+** DONE Ship release
+   CLOSED: [2024-01-30 Tue 17:02] SCHEDULED: <2024-01-30 Tue 14:00-15:30>
+** Meeting notes
+`
+
+	headings, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(headings) != 2 {
+		t.Fatalf("expected 2 task headings, got %d", len(headings))
+	}
+
+	review := headings[0]
+	if review.Title != "Review PR" || review.Priority != "B" || len(review.Tags) != 2 || review.Tags[1] != "review" {
+		t.Errorf("unexpected heading: %+v", review)
+	}
+	if review.Deadline == nil || review.Deadline.Date != "2024-02-01" {
+		t.Errorf("unexpected deadline: %+v", review.Deadline)
+	}
+
+	ship := headings[1]
+	if !ship.Done || ship.Scheduled == nil || ship.Scheduled.End != "15:30" {
+		t.Errorf("unexpected heading: %+v", ship)
+	}
+
+	req, warnings := ship.CreateRequest(testNames)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if *req.Date != "2024-01-30" || *req.Start != "14:00" || *req.EstimatedTime != 5400 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
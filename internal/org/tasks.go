@@ -0,0 +1,252 @@
+package org
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+)
+
+const (
+	propertyEffort = "Effort"
+	propertyList   = "ELLIE_LIST"
+)
+
+// FromTask converts a task into a heading. Date and start become
+// SCHEDULED (with the estimate as time range), the due date DEADLINE,
+// the label a tag, and the list and estimate are kept as properties.
+func FromTask(task *models.Task, names models.Names, loc *time.Location) Heading {
+	h := Heading{
+		Level:      1,
+		Done:       task.Complete,
+		Title:      strings.Join(strings.Fields(task.Description), " "),
+		Properties: map[string]string{PropertyID: task.ID},
+	}
+
+	if task.Priority != nil {
+		h.Priority = models.PriorityLetter(*task.Priority)
+	}
+
+	if date := task.GetDateString(); date != "" {
+		ts := &Timestamp{Date: date}
+		if start, ok := dateutil.ParseStart(date, task.GetStartString(), loc); ok {
+			ts.Date = dateutil.Format(start)
+			ts.Start = start.Format("15:04")
+			if task.EstimatedTime != nil && *task.EstimatedTime > 0 {
+				ts.End = start.Add(time.Duration(*task.EstimatedTime) * time.Second).Format("15:04")
+			}
+		}
+		h.Scheduled = ts
+	}
+	if due, ok := dateutil.ParseDay(task.GetDueDateString(), loc); ok {
+		h.Deadline = &Timestamp{Date: dateutil.Format(due)}
+	}
+	if task.Complete {
+		if completed, err := time.Parse(time.RFC3339, task.GetCompletedAtString()); err == nil {
+			completed = completed.In(loc)
+			h.Closed = &Timestamp{Date: dateutil.Format(completed), Start: completed.Format("15:04")}
+		}
+	}
+
+	if task.Label != nil {
+		if name, ok := names.Labels[*task.Label]; ok {
+			h.Tags = append(h.Tags, tagName(name))
+		}
+	}
+	if task.ListID != nil {
+		if title, ok := names.Lists[*task.ListID]; ok {
+			h.Properties[propertyList] = title
+		}
+	}
+	if task.EstimatedTime != nil && *task.EstimatedTime > 0 {
+		minutes := *task.EstimatedTime / 60
+		h.Properties[propertyEffort] = fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+	}
+
+	return h
+}
+
+// fields is the task state described by a heading
+type fields struct {
+	date     *string
+	start    *string
+	due      *string
+	estimate *int
+	priority *int
+	label    *string
+	list     *string
+}
+
+func (h *Heading) fields(names models.Names) (fields, []string) {
+	var f fields
+	var warnings []string
+
+	if h.Scheduled != nil {
+		date := h.Scheduled.Date
+		f.date = &date
+		if h.Scheduled.Start != "" {
+			start := h.Scheduled.Start
+			f.start = &start
+		}
+	}
+	if h.Deadline != nil {
+		due := h.Deadline.Date
+		f.due = &due
+	}
+
+	if effort, ok := h.property(propertyEffort); ok {
+		if minutes, err := parseEffort(effort); err == nil {
+			seconds := minutes * 60
+			f.estimate = &seconds
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	} else if h.Scheduled != nil && h.Scheduled.Start != "" && h.Scheduled.End != "" {
+		start, err1 := time.Parse("15:04", h.Scheduled.Start)
+		end, err2 := time.Parse("15:04", h.Scheduled.End)
+		if err1 == nil && err2 == nil && end.After(start) {
+			seconds := int(end.Sub(start) / time.Second)
+			f.estimate = &seconds
+		}
+	}
+
+	if p := models.PriorityFromLetter(h.Priority); p > 0 {
+		f.priority = &p
+	}
+
+	for _, tag := range h.Tags {
+		id, err := resolve.LabelID(names, tag)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("tag :%s: ignored: %v", tag, err))
+		case f.label == nil:
+			f.label = &id
+		default:
+			warnings = append(warnings, fmt.Sprintf("tag :%s: ignored, tasks have a single label", tag))
+		}
+	}
+
+	if title, ok := h.property(propertyList); ok && title != "" {
+		if id, err := resolve.ListID(names, title); err == nil {
+			f.list = &id
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	return f, warnings
+}
+
+// CreateRequest builds a request creating the task described by the heading
+func (h *Heading) CreateRequest(names models.Names) (*models.CreateTaskRequest, []string) {
+	f, warnings := h.fields(names)
+	return &models.CreateTaskRequest{
+		Description:   h.Title,
+		Date:          f.date,
+		Start:         f.start,
+		DueDate:       f.due,
+		EstimatedTime: f.estimate,
+		Priority:      f.priority,
+		Label:         f.label,
+		ListID:        f.list,
+	}, warnings
+}
+
+// UpdateRequest builds a request changing only the fields where the
+// heading differs from the task. It returns nil when nothing changed.
+func (h *Heading) UpdateRequest(task *models.Task, names models.Names, loc *time.Location) (*models.UpdateTaskRequest, []string) {
+	f, warnings := h.fields(names)
+	req := &models.UpdateTaskRequest{}
+	changed := false
+
+	if h.Title != strings.Join(strings.Fields(task.Description), " ") {
+		title := h.Title
+		req.Description = &title
+		changed = true
+	}
+	if h.Done != task.Complete {
+		done := h.Done
+		req.Complete = &done
+		changed = true
+	}
+
+	current := FromTask(task, names, loc)
+	var currentDate, currentStart string
+	if current.Scheduled != nil {
+		currentDate, currentStart = current.Scheduled.Date, current.Scheduled.Start
+	}
+	if f.date != nil && *f.date != currentDate {
+		req.Date = f.date
+		changed = true
+	}
+	if f.start != nil && *f.start != currentStart {
+		req.Start = f.start
+		changed = true
+	}
+	if f.due != nil && (current.Deadline == nil || *f.due != current.Deadline.Date) {
+		req.DueDate = f.due
+		changed = true
+	}
+	if f.estimate != nil && (task.EstimatedTime == nil || *f.estimate != *task.EstimatedTime) {
+		req.EstimatedTime = f.estimate
+		changed = true
+	}
+	if f.priority != nil && (task.Priority == nil || *f.priority != *task.Priority) {
+		req.Priority = f.priority
+		changed = true
+	}
+	if f.label != nil && (task.Label == nil || *f.label != *task.Label) {
+		req.Label = f.label
+		changed = true
+	}
+	if f.list != nil && (task.ListID == nil || *f.list != *task.ListID) {
+		req.ListID = f.list
+		changed = true
+	}
+
+	if !changed {
+		return nil, warnings
+	}
+	return req, warnings
+}
+
+func (h *Heading) property(name string) (string, bool) {
+	v, ok := h.Properties[strings.ToUpper(name)]
+	if !ok {
+		v, ok = h.Properties[name]
+	}
+	return v, ok
+}
+
+// parseEffort parses an org Effort value such as 0:30, 1:15 or 45
+func parseEffort(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if hours, minutes, ok := strings.Cut(s, ":"); ok {
+		h, err1 := strconv.Atoi(hours)
+		m, err2 := strconv.Atoi(minutes)
+		if err1 == nil && err2 == nil && h >= 0 && m >= 0 {
+			return h*60 + m, nil
+		}
+	} else if m, err := strconv.Atoi(s); err == nil && m >= 0 {
+		return m, nil
+	}
+	return 0, fmt.Errorf("invalid Effort %q", s)
+}
+
+// tagName turns a label name into a valid org tag
+func tagName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '_' || r == '@' || r == '#' || r == '%':
+			return r
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r > 127:
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimSpace(name))
+}
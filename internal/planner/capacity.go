@@ -0,0 +1,145 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// Capacity holds the working minutes available on each weekday, indexed
+// by time.Weekday
+type Capacity [7]int
+
+// DefaultCapacity is eight hours on weekdays and nothing on weekends
+var DefaultCapacity = Capacity{0, 480, 480, 480, 480, 480, 0}
+
+// DayNames are the short weekday names used in config keys and flags,
+// indexed by time.Weekday
+var DayNames = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// For returns the capacity of the day containing t
+func (c Capacity) For(t time.Time) int {
+	return c[t.Weekday()]
+}
+
+// ParseWeekdays parses a weekday selection such as "mon", "mon,wed",
+// "mon-fri", "weekdays", "weekend" or "all"
+func ParseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case "all":
+			part = "sun-sat"
+		case "weekdays":
+			part = "mon-fri"
+		case "weekend":
+			part = "sat,sun"
+		}
+
+		for _, item := range strings.Split(part, ",") {
+			from, to, isRange := strings.Cut(item, "-")
+			first, ok := weekday(from)
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday %q: expected mon, tue, ..., weekdays, weekend or all", from)
+			}
+			last := first
+			if isRange {
+				if last, ok = weekday(to); !ok {
+					return nil, fmt.Errorf("invalid weekday %q: expected mon, tue, ..., weekdays, weekend or all", to)
+				}
+			}
+			for d := first; ; d = (d + 1) % 7 {
+				days = append(days, d)
+				if d == last {
+					break
+				}
+			}
+		}
+	}
+	return days, nil
+}
+
+func weekday(name string) (time.Weekday, bool) {
+	for i, day := range DayNames {
+		if name == day {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// DayLoad is the planned work on a day compared to its capacity
+type DayLoad struct {
+	Date     string `json:"date"`
+	Weekday  string `json:"weekday"`
+	Capacity int    `json:"capacity_minutes"`
+	Planned  int    `json:"planned_minutes"`
+	Tasks    int    `json:"tasks"`
+	// Over is the number of minutes planned beyond capacity
+	Over          int           `json:"over_minutes"`
+	Overcommitted bool          `json:"overcommitted"`
+	Unestimated   []models.Task `json:"unestimated"`
+}
+
+// Load returns the planned minutes on each date, counting completed tasks
+// as well since they took up the day's time too
+func Load(dates []string, tasks []models.Task, capacity Capacity, loc *time.Location) []DayLoad {
+	byDate := make(map[string][]models.Task)
+	for _, task := range tasks {
+		byDate[task.GetDateString()] = append(byDate[task.GetDateString()], task)
+	}
+
+	loads := make([]DayLoad, 0, len(dates))
+	for _, date := range dates {
+		day, err := time.ParseInLocation(dateutil.Layout, date, loc)
+		if err != nil {
+			continue
+		}
+		load := DayLoad{
+			Date:        date,
+			Weekday:     DayNames[day.Weekday()],
+			Capacity:    capacity.For(day),
+			Unestimated: []models.Task{},
+		}
+		for i := range byDate[date] {
+			task := &byDate[date][i]
+			load.Tasks++
+			if minutes := Estimate(task); minutes > 0 {
+				load.Planned += minutes
+			} else if !task.Complete {
+				load.Unestimated = append(load.Unestimated, *task)
+			}
+		}
+		load.check()
+		loads = append(loads, load)
+	}
+	return loads
+}
+
+// Add counts another task of the given length towards the day
+func (d *DayLoad) Add(minutes int) {
+	d.Planned += minutes
+	d.Tasks++
+	d.check()
+}
+
+func (d *DayLoad) check() {
+	d.Over = 0
+	if d.Planned > d.Capacity {
+		d.Over = d.Planned - d.Capacity
+	}
+	d.Overcommitted = d.Over > 0
+}
+
+// Percent returns the planned minutes as a percentage of capacity, or -1
+// for days without capacity
+func (d *DayLoad) Percent() int {
+	if d.Capacity == 0 {
+		return -1
+	}
+	return d.Planned * 100 / d.Capacity
+}
@@ -0,0 +1,86 @@
+package planner
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []time.Weekday
+	}{
+		{"mon", []time.Weekday{time.Monday}},
+		{"mon,wed", []time.Weekday{time.Monday, time.Wednesday}},
+		{"weekdays", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"weekend", []time.Weekday{time.Saturday, time.Sunday}},
+		{"fri-mon", []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeekdays(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.in, tt.want, got)
+		}
+	}
+
+	all, err := ParseWeekdays("all")
+	if err != nil || len(all) != 7 {
+		t.Errorf("expected all seven days, got %v (%v)", all, err)
+	}
+	if _, err := ParseWeekdays("monday"); err == nil {
+		t.Error("expected error for monday")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	onDay := func(id, date string, minutes int) models.Task {
+		task := task(id, minutes, 0)
+		task.Date, _ = json.Marshal(date)
+		return task
+	}
+	done := onDay("done", "2024-01-15", 0)
+	done.Complete = true
+	tasks := []models.Task{
+		onDay("a", "2024-01-15", 300), // Monday
+		onDay("b", "2024-01-15", 240),
+		onDay("c", "2024-01-15", 0),
+		done,
+		onDay("d", "2024-01-16", 60),
+		onDay("e", "2024-01-20", 30), // Saturday
+	}
+
+	loads := Load([]string{"2024-01-15", "2024-01-16", "2024-01-20"}, tasks, DefaultCapacity, time.UTC)
+	if len(loads) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(loads))
+	}
+
+	mon := loads[0]
+	if mon.Weekday != "mon" || mon.Planned != 540 || mon.Tasks != 4 || mon.Over != 60 || !mon.Overcommitted || mon.Percent() != 112 {
+		t.Errorf("unexpected Monday %+v", mon)
+	}
+	if len(mon.Unestimated) != 1 || mon.Unestimated[0].ID != "c" {
+		t.Errorf("expected c to be unestimated, got %+v", mon.Unestimated)
+	}
+
+	tue := loads[1]
+	if tue.Overcommitted || tue.Planned != 60 {
+		t.Errorf("unexpected Tuesday %+v", tue)
+	}
+	tue.Add(450)
+	if !tue.Overcommitted || tue.Over != 30 || tue.Tasks != 2 {
+		t.Errorf("expected Tuesday to be 30 minutes over, got %+v", tue)
+	}
+
+	sat := loads[2]
+	if !sat.Overcommitted || sat.Capacity != 0 || sat.Percent() != -1 {
+		t.Errorf("unexpected Saturday %+v", sat)
+	}
+}
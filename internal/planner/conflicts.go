@@ -0,0 +1,81 @@
+package planner
+
+import (
+	"sort"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// ConflictKind tells what is wrong with a set of timed tasks
+type ConflictKind string
+
+const (
+	// ConflictOverlap is two tasks taking up the same time
+	ConflictOverlap ConflictKind = "overlap"
+	// ConflictNoBuffer is a task starting less than the buffer after the
+	// previous one ends
+	ConflictNoBuffer ConflictKind = "no-buffer"
+	// ConflictAfterHours is a task ending after working hours
+	ConflictAfterHours ConflictKind = "after-hours"
+)
+
+// Conflict is a problem with one or two timed tasks. Start and End span
+// the overlap, the missing buffer or the time past working hours.
+type Conflict struct {
+	Kind  ConflictKind  `json:"kind"`
+	Start Clock         `json:"start"`
+	End   Clock         `json:"end"`
+	Tasks []models.Task `json:"tasks"`
+}
+
+// Conflicts checks the fixed blocks of a day for overlaps, gaps shorter
+// than buffer minutes and tasks running past the working hours. Completed
+// tasks are ignored.
+func Conflicts(fixed []Block, hours Window, buffer int) []Conflict {
+	var blocks []Block
+	for _, b := range fixed {
+		if b.Task == nil || !b.Task.Complete {
+			blocks = append(blocks, b)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].Start < blocks[j].Start
+	})
+
+	conflicts := []Conflict{}
+	var latest Block
+	for i, a := range blocks {
+		for _, b := range blocks[i+1:] {
+			if b.Start < a.End || b.Start == a.Start {
+				conflicts = append(conflicts, newConflict(ConflictOverlap, b.Start, min(a.End, b.End), a, b))
+			}
+		}
+
+		// The next task to start can only be too close to the task that
+		// ends last so far; a short task inside a long one does not count
+		if i == 0 || a.End > latest.End {
+			latest = a
+		}
+		if i+1 < len(blocks) {
+			next := blocks[i+1]
+			if gap := int(next.Start - latest.End); gap >= 0 && gap < buffer && latest.End > latest.Start {
+				conflicts = append(conflicts, newConflict(ConflictNoBuffer, latest.End, next.Start, latest, next))
+			}
+		}
+
+		if a.End > hours.End {
+			conflicts = append(conflicts, newConflict(ConflictAfterHours, max(a.Start, hours.End), a.End, a))
+		}
+	}
+	return conflicts
+}
+
+func newConflict(kind ConflictKind, start, end Clock, blocks ...Block) Conflict {
+	c := Conflict{Kind: kind, Start: start, End: end}
+	for _, b := range blocks {
+		if b.Task != nil {
+			c.Tasks = append(c.Tasks, *b.Task)
+		}
+	}
+	return c
+}
@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+type wantConflict struct {
+	kind       ConflictKind
+	start, end string
+	ids        string
+}
+
+func TestConflicts(t *testing.T) {
+	done := timed("done", "09:00", 60)
+	done.Complete = true
+
+	tests := []struct {
+		name  string
+		tasks []models.Task
+		want  []wantConflict
+	}{
+		{
+			name: "mixed",
+			tasks: []models.Task{
+				timed("a", "09:00", 60),
+				timed("b", "09:30", 60),
+				timed("c", "10:30", 30),
+				timed("d", "11:05", 30),
+				timed("e", "16:30", 60),
+				timed("f", "16:45", 0),
+				done,
+			},
+			want: []wantConflict{
+				{ConflictOverlap, "09:30", "10:00", "a,b"},
+				{ConflictNoBuffer, "10:30", "10:30", "b,c"},
+				{ConflictNoBuffer, "11:00", "11:05", "c,d"},
+				{ConflictOverlap, "16:45", "16:45", "e,f"},
+				{ConflictAfterHours, "17:00", "17:30", "e"},
+			},
+		},
+		{
+			name: "short task inside a long one",
+			tasks: []models.Task{
+				timed("a", "09:00", 180),
+				timed("b", "09:30", 30),
+				timed("c", "12:02", 30),
+			},
+			want: []wantConflict{
+				{ConflictOverlap, "09:30", "10:00", "a,b"},
+				{ConflictNoBuffer, "12:00", "12:02", "a,c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		fixed, _ := Split(tt.tasks, "2024-01-15", time.UTC)
+		got := Conflicts(fixed, Window{Start: 540, End: 1020}, 10)
+
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %d conflicts, got %+v", tt.name, len(tt.want), got)
+			continue
+		}
+		for i, w := range tt.want {
+			c := got[i]
+			ids := ""
+			for j, task := range c.Tasks {
+				if j > 0 {
+					ids += ","
+				}
+				ids += task.ID
+			}
+			if c.Kind != w.kind || c.Start.String() != w.start || c.End.String() != w.end || ids != w.ids {
+				t.Errorf("%s: conflict %d: expected %s %s-%s %s, got %s %s-%s %s", tt.name, i, w.kind, w.start, w.end, w.ids, c.Kind, c.Start, c.End, ids)
+			}
+		}
+	}
+}
+
+func TestConflictsNone(t *testing.T) {
+	fixed, _ := Split([]models.Task{timed("a", "09:00", 30), timed("b", "10:00", 30)}, "2024-01-15", time.UTC)
+	if got := Conflicts(fixed, Window{Start: 540, End: 1020}, 5); len(got) != 0 {
+		t.Errorf("expected no conflicts, got %+v", got)
+	}
+}
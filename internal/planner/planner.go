@@ -0,0 +1,294 @@
+// Package planner timeboxes a day's tasks around fixed appointments
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// Clock is a time of day in minutes since midnight
+type Clock int
+
+// ParseClock parses a time of day such as "09:00" or "9:30"
+func ParseClock(s string) (Clock, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(s), ":")
+	h, err1 := strconv.Atoi(hours)
+	m, err2 := strconv.Atoi(minutes)
+	if !ok || err1 != nil || err2 != nil || h < 0 || h > 24 || m < 0 || m > 59 || h == 24 && m > 0 {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	return Clock(h*60 + m), nil
+}
+
+// ClockOf returns the time of day of t
+func ClockOf(t time.Time) Clock {
+	return Clock(t.Hour()*60 + t.Minute())
+}
+
+// String formats the clock as HH:MM
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// MarshalJSON encodes the clock as an HH:MM string
+func (c Clock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// Window is a span of the day such as working hours or a break
+type Window struct {
+	Start Clock `json:"start"`
+	End   Clock `json:"end"`
+}
+
+// ParseWindow parses a span such as "09:00-17:00"
+func ParseWindow(s string) (Window, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid time range %q: expected HH:MM-HH:MM", s)
+	}
+	start, err := ParseClock(from)
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := ParseClock(to)
+	if err != nil {
+		return Window{}, err
+	}
+	if end <= start {
+		return Window{}, fmt.Errorf("invalid time range %q: end must be after start", s)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+// Minutes returns the length of the window
+func (w Window) Minutes() int {
+	return int(w.End - w.Start)
+}
+
+// String formats the window as HH:MM-HH:MM
+func (w Window) String() string {
+	return w.Start.String() + "-" + w.End.String()
+}
+
+// Kind tells what occupies a block
+type Kind string
+
+const (
+	KindFixed     Kind = "fixed"
+	KindScheduled Kind = "scheduled"
+	KindBreak     Kind = "break"
+)
+
+// Block is a span of the day taken by a task or a break
+type Block struct {
+	Kind  Kind         `json:"kind"`
+	Start Clock        `json:"start"`
+	End   Clock        `json:"end"`
+	Task  *models.Task `json:"task,omitempty"`
+}
+
+// Unplaced is a task the planner could not fit into the day
+type Unplaced struct {
+	Task   models.Task `json:"task"`
+	Reason string      `json:"reason"`
+}
+
+// Plan is the timeline for a day
+type Plan struct {
+	Blocks   []Block    `json:"blocks"`
+	Unplaced []Unplaced `json:"unplaced"`
+}
+
+// Scheduled returns the blocks the planner placed
+func (p *Plan) Scheduled() []Block {
+	var blocks []Block
+	for _, b := range p.Blocks {
+		if b.Kind == KindScheduled {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// Options controls Schedule
+type Options struct {
+	Hours  Window
+	Breaks []Window
+	// Buffer is the number of minutes kept free after every task
+	Buffer int
+	// Earliest keeps tasks from being placed before this time, e.g. now
+	Earliest Clock
+	// DefaultEstimate is used for tasks without an estimate. With zero
+	// such tasks are left unplaced.
+	DefaultEstimate int
+	// Step rounds start times up to a multiple of this many minutes
+	Step int
+}
+
+// Estimate returns a task's estimate in whole minutes, rounding up
+func Estimate(task *models.Task) int {
+	if task.EstimatedTime == nil {
+		return 0
+	}
+	return Minutes(*task.EstimatedTime)
+}
+
+// Minutes converts an estimate in seconds to whole minutes, rounding up
+func Minutes(seconds int) int {
+	if seconds <= 0 {
+		return 0
+	}
+	return (seconds + 59) / 60
+}
+
+// Split separates tasks with a start time on date into fixed blocks. The
+// remaining incomplete tasks are returned as open.
+func Split(tasks []models.Task, date string, loc *time.Location) (fixed []Block, open []models.Task) {
+	for i := range tasks {
+		task := &tasks[i]
+		if start, ok := dateutil.ParseStart(task.GetDateString(), task.GetStartString(), loc); ok && dateutil.Format(start) == date {
+			c := ClockOf(start)
+			fixed = append(fixed, Block{Kind: KindFixed, Start: c, End: c + Clock(Estimate(task)), Task: task})
+			continue
+		}
+		if !task.Complete {
+			open = append(open, *task)
+		}
+	}
+	return fixed, open
+}
+
+// Sort orders tasks for scheduling: higher priority first, then earlier
+// due dates, otherwise keeping the given order
+func Sort(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		pi, pj := priority(&tasks[i]), priority(&tasks[j])
+		if pi != pj {
+			return pi > pj
+		}
+		di, dj := tasks[i].GetDueDateString(), tasks[j].GetDueDateString()
+		if di != dj {
+			return dj == "" || di != "" && di < dj
+		}
+		return false
+	})
+}
+
+// SortByStart orders tasks for display: tasks with a start time first,
+// earliest first, then the others in the given order
+func SortByStart(tasks []models.Task, loc *time.Location) {
+	type entry struct {
+		task  models.Task
+		start time.Time
+		timed bool
+	}
+
+	entries := make([]entry, len(tasks))
+	for i, task := range tasks {
+		start, ok := dateutil.ParseStart(task.GetDateString(), task.GetStartString(), loc)
+		entries[i] = entry{task: task, start: start, timed: ok}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].timed != entries[j].timed {
+			return entries[i].timed
+		}
+		return entries[i].timed && entries[i].start.Before(entries[j].start)
+	})
+
+	for i, e := range entries {
+		tasks[i] = e.task
+	}
+}
+
+// Schedule places tasks, in priority order, at the earliest gap in the
+// working hours that is free of fixed blocks, breaks and earlier
+// placements, keeping opts.Buffer minutes after every task
+func Schedule(fixed []Block, tasks []models.Task, opts Options) *Plan {
+	if opts.Step <= 0 {
+		opts.Step = 1
+	}
+
+	plan := &Plan{Unplaced: []Unplaced{}}
+	plan.Blocks = append(plan.Blocks, fixed...)
+	for _, b := range opts.Breaks {
+		if b.End > opts.Hours.Start && b.Start < opts.Hours.End {
+			plan.Blocks = append(plan.Blocks, Block{Kind: KindBreak, Start: b.Start, End: b.End})
+		}
+	}
+
+	tasks = append([]models.Task(nil), tasks...)
+	Sort(tasks)
+
+	earliest := opts.Hours.Start
+	if opts.Earliest > earliest {
+		earliest = opts.Earliest
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		minutes := Estimate(task)
+		if minutes == 0 {
+			minutes = opts.DefaultEstimate
+		}
+		if minutes == 0 {
+			plan.Unplaced = append(plan.Unplaced, Unplaced{Task: *task, Reason: "no estimate"})
+			continue
+		}
+
+		start, ok := findGap(plan.Blocks, earliest, Clock(minutes), &opts)
+		if !ok {
+			plan.Unplaced = append(plan.Unplaced, Unplaced{Task: *task, Reason: "does not fit"})
+			continue
+		}
+		plan.Blocks = append(plan.Blocks, Block{Kind: KindScheduled, Start: start, End: start + Clock(minutes), Task: task})
+	}
+
+	sort.SliceStable(plan.Blocks, func(i, j int) bool {
+		return plan.Blocks[i].Start < plan.Blocks[j].Start
+	})
+	return plan
+}
+
+// findGap returns the earliest start from which a task of the given
+// length fits between the existing blocks
+func findGap(blocks []Block, earliest, length Clock, opts *Options) (Clock, bool) {
+	buffer := Clock(opts.Buffer)
+	start := roundUp(earliest, opts.Step)
+
+	for moved := true; moved; {
+		moved = false
+		for _, b := range blocks {
+			end, pad := b.End, Clock(0)
+			if b.Kind != KindBreak {
+				end, pad = b.End+buffer, buffer
+			}
+			if start < end && start+length+pad > b.Start {
+				start = roundUp(end, opts.Step)
+				moved = true
+			}
+		}
+	}
+
+	return start, start+length <= opts.Hours.End
+}
+
+func roundUp(c Clock, step int) Clock {
+	s := Clock(step)
+	return (c + s - 1) / s * s
+}
+
+func priority(task *models.Task) int {
+	if task.Priority == nil {
+		return 0
+	}
+	return *task.Priority
+}
@@ -0,0 +1,169 @@
+package planner
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func task(id string, minutes, priority int) models.Task {
+	t := models.Task{ID: id, Description: "Task " + id}
+	t.Date, _ = json.Marshal("2024-01-15")
+	if minutes > 0 {
+		t.EstimatedTime = intPtr(minutes * 60)
+	}
+	if priority > 0 {
+		t.Priority = intPtr(priority)
+	}
+	return t
+}
+
+func timed(id, start string, minutes int) models.Task {
+	t := task(id, minutes, 0)
+	t.Start, _ = json.Marshal(start)
+	return t
+}
+
+func TestParseWindow(t *testing.T) {
+	w, err := ParseWindow("09:00-17:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Start != 540 || w.End != 1050 || w.String() != "09:00-17:30" || w.Minutes() != 510 {
+		t.Errorf("unexpected window %+v", w)
+	}
+
+	for _, s := range []string{"09:00", "17:00-09:00", "9-17", "25:00-26:00", "09:60-10:00"} {
+		if _, err := ParseWindow(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	done := task("done", 30, 0)
+	done.Complete = true
+	tasks := []models.Task{timed("meeting", "10:00", 60), task("open", 30, 0), done}
+
+	fixed, open := Split(tasks, "2024-01-15", time.UTC)
+	if len(fixed) != 1 || fixed[0].Start != 600 || fixed[0].End != 660 {
+		t.Errorf("unexpected fixed blocks %+v", fixed)
+	}
+	if len(open) != 1 || open[0].ID != "open" {
+		t.Errorf("unexpected open tasks %+v", open)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	fixed, open := Split([]models.Task{
+		timed("meeting", "10:00", 60),
+		task("low", 30, 1),
+		task("urgent", 45, 4),
+		task("none", 0, 3),
+		task("big", 300, 2),
+		task("medium", 20, 2),
+	}, "2024-01-15", time.UTC)
+
+	plan := Schedule(fixed, open, Options{
+		Hours:  Window{Start: 540, End: 780},
+		Breaks: []Window{{Start: 720, End: 750}},
+		Buffer: 10,
+		Step:   5,
+	})
+
+	got := plan.Blocks
+	if len(got) != 5 {
+		t.Fatalf("expected 5 blocks, got %+v", got)
+	}
+	for i, w := range []struct {
+		id         string
+		kind       Kind
+		start, end string
+	}{
+		{"urgent", KindScheduled, "09:00", "09:45"},
+		{"meeting", KindFixed, "10:00", "11:00"},
+		{"medium", KindScheduled, "11:10", "11:30"},
+		{"", KindBreak, "12:00", "12:30"},
+		{"low", KindScheduled, "12:30", "13:00"},
+	} {
+		b := got[i]
+		id := ""
+		if b.Task != nil {
+			id = b.Task.ID
+		}
+		if id != w.id || b.Kind != w.kind || b.Start.String() != w.start || b.End.String() != w.end {
+			t.Errorf("block %d: expected %s %s %s-%s, got %s %s %s-%s", i, w.id, w.kind, w.start, w.end, id, b.Kind, b.Start, b.End)
+		}
+	}
+
+	if len(plan.Unplaced) != 2 || plan.Unplaced[0].Task.ID != "none" || plan.Unplaced[0].Reason != "no estimate" ||
+		plan.Unplaced[1].Task.ID != "big" || plan.Unplaced[1].Reason != "does not fit" {
+		t.Errorf("unexpected unplaced tasks %+v", plan.Unplaced)
+	}
+	if len(plan.Scheduled()) != 3 {
+		t.Errorf("expected 3 scheduled blocks, got %d", len(plan.Scheduled()))
+	}
+}
+
+func TestScheduleEarliestAndDefaultEstimate(t *testing.T) {
+	plan := Schedule(nil, []models.Task{task("a", 0, 0)}, Options{
+		Hours:           Window{Start: 540, End: 1020},
+		Earliest:        601,
+		DefaultEstimate: 25,
+		Step:            15,
+	})
+
+	if len(plan.Blocks) != 1 || plan.Blocks[0].Start.String() != "10:15" || plan.Blocks[0].End.String() != "10:40" {
+		t.Errorf("unexpected blocks %+v", plan.Blocks)
+	}
+}
+
+func TestSort(t *testing.T) {
+	a, b, c := task("a", 10, 2), task("b", 10, 2), task("c", 10, 2)
+	b.DueDate, _ = json.Marshal("2024-01-20")
+	c.DueDate, _ = json.Marshal("2024-01-18")
+	tasks := []models.Task{a, b, c, task("d", 10, 4)}
+
+	Sort(tasks)
+	if tasks[0].ID != "d" || tasks[1].ID != "c" || tasks[2].ID != "b" || tasks[3].ID != "a" {
+		t.Errorf("unexpected order %s %s %s %s", tasks[0].ID, tasks[1].ID, tasks[2].ID, tasks[3].ID)
+	}
+}
+
+func TestMinutes(t *testing.T) {
+	tests := map[int]int{0: 0, -60: 0, 60: 1, 61: 2, 1800: 30, 1799: 30}
+	for seconds, expected := range tests {
+		if got := Minutes(seconds); got != expected {
+			t.Errorf("Minutes(%d): expected %d, got %d", seconds, expected, got)
+		}
+	}
+}
+
+func TestSortByStart(t *testing.T) {
+	tasks := []models.Task{
+		task("a", 30, 0),
+		timed("b", "14:00", 30),
+		task("c", 30, 0),
+		timed("d", "09:00", 30),
+	}
+
+	SortByStart(tasks, time.UTC)
+
+	got := ""
+	for _, task := range tasks {
+		got += task.ID
+	}
+	if got != "dbac" {
+		t.Errorf("expected order dbac, got %s", got)
+	}
+}
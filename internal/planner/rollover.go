@@ -0,0 +1,52 @@
+package planner
+
+import (
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// Rollover is an unfinished task to be moved off a past day. An empty To
+// means the braindump.
+type Rollover struct {
+	Task  models.Task `json:"task"`
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Start string      `json:"start,omitempty"`
+}
+
+// NewRollover plans moving task from the day from to the day to, or to the
+// braindump. The start time is only kept with keepStart, and never in the
+// braindump.
+func NewRollover(task models.Task, from, to string, toBraindump, keepStart bool) Rollover {
+	r := Rollover{Task: task, From: from, To: to}
+	if toBraindump {
+		r.To = ""
+	} else if keepStart {
+		r.Start = task.GetStartString()
+	}
+	return r
+}
+
+// Request returns the update moving the task. The start is only sent
+// when it changes, or cleared when the task goes to the braindump.
+func (r *Rollover) Request() *models.UpdateTaskRequest {
+	date, start := r.To, r.Start
+	req := &models.UpdateTaskRequest{Date: &date}
+	if start != r.Task.GetStartString() || date == "" {
+		req.Start = &start
+	}
+	return req
+}
+
+// RolloverWindow returns the first and last day to collect unfinished
+// tasks from: days back from today up to the day before target. Only past
+// days are rolled over, so the window ends yesterday at the latest; until
+// is before from when there is nothing to roll over.
+func RolloverWindow(today, target time.Time, days int) (from, until time.Time) {
+	from = today.AddDate(0, 0, -days)
+	if target.After(today) {
+		target = today
+	}
+	return from, target.AddDate(0, 0, -1)
+}
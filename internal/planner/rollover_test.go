@@ -0,0 +1,61 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+)
+
+func TestRolloverRequest(t *testing.T) {
+	task := timed("1", "09:30", 30)
+
+	tests := []struct {
+		name        string
+		toBraindump bool
+		keepStart   bool
+		date        string
+		start       *string
+	}{
+		{name: "clear start", date: "2024-01-16", start: strPtr("")},
+		{name: "keep start", keepStart: true, date: "2024-01-16"},
+		{name: "braindump", toBraindump: true, keepStart: true, start: strPtr("")},
+	}
+
+	for _, tt := range tests {
+		r := NewRollover(task, "2024-01-15", "2024-01-16", tt.toBraindump, tt.keepStart)
+		req := r.Request()
+		if req.Date == nil || *req.Date != tt.date {
+			t.Errorf("%s: expected date %q, got %v", tt.name, tt.date, req.Date)
+		}
+		switch {
+		case tt.start == nil && req.Start != nil:
+			t.Errorf("%s: expected start to be left alone, got %q", tt.name, *req.Start)
+		case tt.start != nil && (req.Start == nil || *req.Start != *tt.start):
+			t.Errorf("%s: expected start %q, got %v", tt.name, *tt.start, req.Start)
+		}
+	}
+}
+
+func TestRolloverWindow(t *testing.T) {
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		target string
+		from   string
+		until  string
+	}{
+		{"2024-01-15", "2024-01-08", "2024-01-14"},
+		// Future targets must not pick up today's or later tasks
+		{"2024-01-18", "2024-01-08", "2024-01-14"},
+		{"2024-01-12", "2024-01-08", "2024-01-11"},
+	}
+
+	for _, tt := range tests {
+		target, _ := time.Parse(dateutil.Layout, tt.target)
+		from, until := RolloverWindow(today, target, 7)
+		if dateutil.Format(from) != tt.from || dateutil.Format(until) != tt.until {
+			t.Errorf("target %s: expected %s..%s, got %s..%s", tt.target, tt.from, tt.until, dateutil.Format(from), dateutil.Format(until))
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// NoLabel and NoList name the groups of tasks without a label or list
+const (
+	NoLabel = "(no label)"
+	NoList  = "(no list)"
+)
+
+// Sample is a completed task with both an estimate and tracked time
+type Sample struct {
+	TaskID      string  `json:"task_id"`
+	Description string  `json:"description"`
+	Date        string  `json:"date"`
+	Label       string  `json:"label"`
+	List        string  `json:"list"`
+	Estimated   int     `json:"estimated_seconds"`
+	Actual      int     `json:"actual_seconds"`
+	Ratio       float64 `json:"ratio"`
+}
+
+// Miss returns how far the estimate was off, in seconds
+func (s *Sample) Miss() int {
+	return s.Actual - s.Estimated
+}
+
+// Accuracy summarises a group of samples. Ratio is actual over estimated
+// time, so values above 1 mean the work took longer than planned. Error
+// is the mean absolute error relative to the estimate.
+type Accuracy struct {
+	Name      string  `json:"name"`
+	Tasks     int     `json:"tasks"`
+	Estimated int     `json:"estimated_seconds"`
+	Actual    int     `json:"actual_seconds"`
+	Ratio     float64 `json:"ratio"`
+	Error     float64 `json:"error"`
+}
+
+// EstimateReport compares estimated against actual time
+type EstimateReport struct {
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	Completed   int        `json:"completed"`
+	Unestimated int        `json:"unestimated"`
+	Untracked   int        `json:"untracked"`
+	Overall     Accuracy   `json:"overall"`
+	ByLabel     []Accuracy `json:"by_label"`
+	ByList      []Accuracy `json:"by_list"`
+	ByWeek      []Accuracy `json:"by_week"`
+	WorstMisses []Sample   `json:"worst_misses"`
+}
+
+// Estimates builds the report from the tasks of a date range. Only
+// completed tasks with both an estimate and tracked time are compared;
+// the others are counted so gaps in tracking are visible. Weeks are named
+// by their Monday.
+func Estimates(from, to string, tasks []models.Task, names models.Names, worst int) *EstimateReport {
+	r := &EstimateReport{From: from, To: to}
+
+	var samples []Sample
+	for i := range tasks {
+		task := &tasks[i]
+		if !task.Complete {
+			continue
+		}
+		r.Completed++
+
+		if task.EstimatedTime == nil || *task.EstimatedTime <= 0 {
+			r.Unestimated++
+			continue
+		}
+		if task.ActualTime == nil || *task.ActualTime <= 0 {
+			r.Untracked++
+			continue
+		}
+
+		s := Sample{
+			TaskID:      task.ID,
+			Description: task.Description,
+			Date:        task.GetDateString(),
+			Label:       LabelName(task, names),
+			List:        ListTitle(task, names),
+			Estimated:   *task.EstimatedTime,
+			Actual:      *task.ActualTime,
+		}
+		s.Ratio = float64(s.Actual) / float64(s.Estimated)
+		samples = append(samples, s)
+	}
+
+	r.Overall = accuracy("overall", samples)
+	r.ByLabel = groupAccuracy(samples, func(s *Sample) string { return s.Label })
+	r.ByList = groupAccuracy(samples, func(s *Sample) string { return s.List })
+	r.ByWeek = groupAccuracy(samples, func(s *Sample) string { return weekOf(s.Date) })
+	sort.Slice(r.ByWeek, func(i, j int) bool { return r.ByWeek[i].Name < r.ByWeek[j].Name })
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return abs(samples[i].Miss()) > abs(samples[j].Miss())
+	})
+	if worst >= 0 && len(samples) > worst {
+		samples = samples[:worst]
+	}
+	r.WorstMisses = samples
+	if r.WorstMisses == nil {
+		r.WorstMisses = []Sample{}
+	}
+
+	return r
+}
+
+// LabelName returns the name of a task's label, or NoLabel
+func LabelName(task *models.Task, names models.Names) string {
+	if task.Label == nil || *task.Label == "" {
+		return NoLabel
+	}
+	if name, ok := names.Labels[*task.Label]; ok {
+		return name
+	}
+	return *task.Label
+}
+
+// ListTitle returns the title of a task's list, or NoList
+func ListTitle(task *models.Task, names models.Names) string {
+	if task.ListID == nil || *task.ListID == "" {
+		return NoList
+	}
+	if title, ok := names.Lists[*task.ListID]; ok {
+		return title
+	}
+	return *task.ListID
+}
+
+func accuracy(name string, samples []Sample) Accuracy {
+	a := Accuracy{Name: name, Tasks: len(samples)}
+	var errSum float64
+	for _, s := range samples {
+		a.Estimated += s.Estimated
+		a.Actual += s.Actual
+		errSum += math.Abs(float64(s.Miss())) / float64(s.Estimated)
+	}
+	if a.Estimated > 0 {
+		a.Ratio = round2(float64(a.Actual) / float64(a.Estimated))
+	}
+	if len(samples) > 0 {
+		a.Error = round2(errSum / float64(len(samples)))
+	}
+	return a
+}
+
+// groupAccuracy summarises samples per key, largest groups first
+func groupAccuracy(samples []Sample, key func(*Sample) string) []Accuracy {
+	groups := make(map[string][]Sample)
+	var order []string
+	for i := range samples {
+		k := key(&samples[i])
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], samples[i])
+	}
+
+	result := make([]Accuracy, 0, len(order))
+	for _, k := range order {
+		result = append(result, accuracy(k, groups[k]))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Tasks != result[j].Tasks {
+			return result[i].Tasks > result[j].Tasks
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// weekOf returns the Monday of the week containing date
+func weekOf(date string) string {
+	t, err := time.Parse(dateutil.Layout, date)
+	if err != nil {
+		return date
+	}
+	offset := (int(t.Weekday()) + 6) % 7
+	return dateutil.Format(t.AddDate(0, 0, -offset))
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var testNames = models.Names{
+	Lists:  map[string]string{"list-1": "Clients"},
+	Labels: map[string]string{"label-1": "Deep Work"},
+}
+
+func task(id, date string, estimated, actual int, complete bool) models.Task {
+	t := models.Task{ID: id, Description: "Task " + id, Complete: complete}
+	t.Date, _ = json.Marshal(date)
+	if estimated > 0 {
+		t.EstimatedTime = intPtr(estimated)
+	}
+	if actual > 0 {
+		t.ActualTime = intPtr(actual)
+	}
+	return t
+}
+
+func TestEstimates(t *testing.T) {
+	a := task("a", "2024-01-15", 3600, 5400, true) // Monday
+	a.Label = strPtr("label-1")
+	b := task("b", "2024-01-17", 1800, 1800, true)
+	b.Label = strPtr("label-1")
+	b.ListID = strPtr("list-1")
+	c := task("c", "2024-01-22", 1200, 600, true)
+	tasks := []models.Task{
+		a, b, c,
+		task("d", "2024-01-22", 0, 600, true),
+		task("e", "2024-01-22", 600, 0, true),
+		task("f", "2024-01-22", 600, 900, false),
+	}
+
+	r := Estimates("2024-01-15", "2024-01-28", tasks, testNames, 2)
+
+	if r.Completed != 5 || r.Unestimated != 1 || r.Untracked != 1 {
+		t.Errorf("unexpected counts: completed %d, unestimated %d, untracked %d", r.Completed, r.Unestimated, r.Untracked)
+	}
+	if r.Overall.Tasks != 3 || r.Overall.Estimated != 6600 || r.Overall.Actual != 7800 {
+		t.Errorf("unexpected overall %+v", r.Overall)
+	}
+	if r.Overall.Ratio != 1.18 {
+		t.Errorf("expected ratio 1.18, got %v", r.Overall.Ratio)
+	}
+	// (0.5 + 0 + 0.5) / 3
+	if r.Overall.Error != 0.33 {
+		t.Errorf("expected error 0.33, got %v", r.Overall.Error)
+	}
+
+	if len(r.ByLabel) != 2 || r.ByLabel[0].Name != "Deep Work" || r.ByLabel[0].Ratio != 1.33 {
+		t.Errorf("unexpected label groups %+v", r.ByLabel)
+	}
+	if len(r.ByList) != 2 || r.ByList[0].Name != NoList || r.ByList[1].Name != "Clients" {
+		t.Errorf("unexpected list groups %+v", r.ByList)
+	}
+	if len(r.ByWeek) != 2 || r.ByWeek[0].Name != "2024-01-15" || r.ByWeek[1].Name != "2024-01-22" {
+		t.Errorf("unexpected weeks %+v", r.ByWeek)
+	}
+
+	if len(r.WorstMisses) != 2 || r.WorstMisses[0].TaskID != "a" || r.WorstMisses[1].TaskID != "c" {
+		t.Errorf("unexpected worst misses %+v", r.WorstMisses)
+	}
+}
+
+func TestEstimatesEmpty(t *testing.T) {
+	r := Estimates("2024-01-15", "2024-01-15", nil, testNames, 5)
+	if r.Overall.Ratio != 0 || len(r.WorstMisses) != 0 || r.WorstMisses == nil {
+		t.Errorf("unexpected empty report %+v", r)
+	}
+}
+
+func TestWeekOf(t *testing.T) {
+	tests := map[string]string{
+		"2024-01-15": "2024-01-15",
+		"2024-01-21": "2024-01-15",
+		"2024-01-22": "2024-01-22",
+	}
+	for date, want := range tests {
+		if got := weekOf(date); got != want {
+			t.Errorf("weekOf(%s) = %s, want %s", date, got, want)
+		}
+	}
+}
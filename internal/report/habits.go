@@ -0,0 +1,76 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// HabitDay is one scheduled occurrence of a habit
+type HabitDay struct {
+	Date string `json:"date"`
+	Done bool   `json:"done"`
+}
+
+// Habit summarises the instances of one recurring task
+type Habit struct {
+	RecurringID   string     `json:"recurring_id"`
+	Description   string     `json:"description"`
+	Scheduled     int        `json:"scheduled"`
+	Completed     int        `json:"completed"`
+	Rate          float64    `json:"rate"`
+	CurrentStreak int        `json:"current_streak"`
+	LongestStreak int        `json:"longest_streak"`
+	Days          []HabitDay `json:"days"`
+}
+
+// Habits groups recurring task instances by series. Streaks count
+// consecutive completed occurrences, so days on which a habit is not
+// scheduled do not break them; an occurrence today that is not done yet
+// does not break the current streak either.
+func Habits(tasks []models.Task, today string) []Habit {
+	index := make(map[string]int)
+	var habits []Habit
+
+	for i := range tasks {
+		task := &tasks[i]
+		if task.RecurringID == nil || *task.RecurringID == "" {
+			continue
+		}
+		date := task.GetDateString()
+		if date == "" {
+			continue
+		}
+
+		n, ok := index[*task.RecurringID]
+		if !ok {
+			n = len(habits)
+			index[*task.RecurringID] = n
+			habits = append(habits, Habit{RecurringID: *task.RecurringID, Description: task.Description})
+		}
+		habits[n].Days = append(habits[n].Days, HabitDay{Date: date, Done: task.Complete})
+	}
+
+	for i := range habits {
+		h := &habits[i]
+		sort.SliceStable(h.Days, func(a, b int) bool { return h.Days[a].Date < h.Days[b].Date })
+
+		done := make([]bool, len(h.Days))
+		for j, d := range h.Days {
+			done[j] = d.Done
+			if d.Done {
+				h.Completed++
+			}
+		}
+		h.Scheduled = len(h.Days)
+		if h.Scheduled > 0 {
+			h.Rate = round2(float64(h.Completed) / float64(h.Scheduled))
+		}
+
+		lastToday := h.Scheduled > 0 && h.Days[h.Scheduled-1].Date == today
+		h.CurrentStreak, h.LongestStreak = Streaks(done, lastToday)
+	}
+
+	sort.SliceStable(habits, func(i, j int) bool { return habits[i].Description < habits[j].Description })
+	return habits
+}
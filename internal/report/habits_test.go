@@ -0,0 +1,50 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func instance(id, series, date string, complete bool) models.Task {
+	t := task(id, date, 0, 0, complete)
+	t.Description = "Habit " + series
+	t.Recurring = true
+	t.RecurringID = strPtr(series)
+	return t
+}
+
+func TestHabits(t *testing.T) {
+	tasks := []models.Task{
+		instance("1", "walk", "2024-01-15", true),
+		instance("2", "walk", "2024-01-17", false),
+		instance("3", "walk", "2024-01-16", true),
+		instance("4", "walk", "2024-01-18", true),
+		instance("5", "walk", "2024-01-19", true),
+		instance("6", "walk", "2024-01-20", false),
+		instance("7", "read", "2024-01-19", true),
+		task("8", "2024-01-19", 0, 0, true),
+	}
+
+	habits := Habits(tasks, "2024-01-20")
+	if len(habits) != 2 || habits[0].RecurringID != "read" {
+		t.Fatalf("unexpected habits %+v", habits)
+	}
+
+	walk := habits[1]
+	if walk.Scheduled != 6 || walk.Completed != 4 || walk.Rate != 0.67 {
+		t.Errorf("unexpected totals %+v", walk)
+	}
+	if walk.Days[1].Date != "2024-01-16" {
+		t.Errorf("days should be sorted, got %+v", walk.Days)
+	}
+	// Today's walk is still open, so the streak of the 18th and 19th holds
+	if walk.CurrentStreak != 2 || walk.LongestStreak != 2 {
+		t.Errorf("unexpected streaks %d/%d", walk.CurrentStreak, walk.LongestStreak)
+	}
+
+	habits = Habits(tasks, "2024-01-21")
+	if habits[1].CurrentStreak != 0 {
+		t.Errorf("a missed past occurrence should break the streak, got %d", habits[1].CurrentStreak)
+	}
+}
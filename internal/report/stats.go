@@ -0,0 +1,177 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// DayCount is the number of tasks planned and completed on a day or week
+type DayCount struct {
+	Date      string `json:"date"`
+	Planned   int    `json:"planned"`
+	Completed int    `json:"completed"`
+}
+
+// NameCount is the number of tasks completed under a label or list
+type NameCount struct {
+	Name      string `json:"name"`
+	Planned   int    `json:"planned"`
+	Completed int    `json:"completed"`
+}
+
+// Stats summarises productivity over a date range
+type Stats struct {
+	From            string      `json:"from"`
+	To              string      `json:"to"`
+	Planned         int         `json:"planned"`
+	Completed       int         `json:"completed"`
+	CompletionRate  float64     `json:"completion_rate"`
+	AveragePriority float64     `json:"average_priority"`
+	CurrentStreak   int         `json:"current_streak"`
+	LongestStreak   int         `json:"longest_streak"`
+	Days            []DayCount  `json:"days"`
+	Weeks           []DayCount  `json:"weeks"`
+	Labels          []NameCount `json:"labels"`
+	Lists           []NameCount `json:"lists"`
+}
+
+// NewStats computes statistics for the dates in order. Tasks count on
+// their planned date; completed tasks count as done on the day they were
+// completed when that is known and inside the range. Streaks count days
+// with at least one completion; when the range ends today, today not
+// having a completion yet does not break the current streak.
+func NewStats(dates []string, today string, tasks []models.Task, names models.Names, loc *time.Location) *Stats {
+	s := &Stats{Days: make([]DayCount, len(dates)), Labels: []NameCount{}, Lists: []NameCount{}}
+	if len(dates) > 0 {
+		s.From, s.To = dates[0], dates[len(dates)-1]
+	}
+
+	dayIndex := make(map[string]int, len(dates))
+	for i, date := range dates {
+		s.Days[i].Date = date
+		dayIndex[date] = i
+	}
+
+	labels := make(map[string]*NameCount)
+	lists := make(map[string]*NameCount)
+	priorities, prioritySum := 0, 0
+
+	for i := range tasks {
+		task := &tasks[i]
+		date := task.GetDateString()
+		if n, ok := dayIndex[date]; ok {
+			s.Days[n].Planned++
+		}
+		s.Planned++
+
+		label := count(labels, LabelName(task, names))
+		list := count(lists, ListTitle(task, names))
+		label.Planned++
+		list.Planned++
+
+		if !task.Complete {
+			continue
+		}
+		s.Completed++
+		label.Completed++
+		list.Completed++
+		if task.Priority != nil && *task.Priority > 0 {
+			priorities++
+			prioritySum += *task.Priority
+		}
+
+		if done, ok := dateutil.ParseDay(task.GetCompletedAtString(), loc); ok {
+			if n, ok := dayIndex[dateutil.Format(done.In(loc))]; ok {
+				s.Days[n].Completed++
+				continue
+			}
+		}
+		if n, ok := dayIndex[date]; ok {
+			s.Days[n].Completed++
+		}
+	}
+
+	if s.Planned > 0 {
+		s.CompletionRate = round2(float64(s.Completed) / float64(s.Planned))
+	}
+	if priorities > 0 {
+		s.AveragePriority = round2(float64(prioritySum) / float64(priorities))
+	}
+
+	done := make([]bool, len(s.Days))
+	weekIndex := make(map[string]int)
+	for i, d := range s.Days {
+		done[i] = d.Completed > 0
+
+		week := weekOf(d.Date)
+		n, ok := weekIndex[week]
+		if !ok {
+			n = len(s.Weeks)
+			weekIndex[week] = n
+			s.Weeks = append(s.Weeks, DayCount{Date: week})
+		}
+		s.Weeks[n].Planned += d.Planned
+		s.Weeks[n].Completed += d.Completed
+	}
+	s.CurrentStreak, s.LongestStreak = Streaks(done, s.To == today)
+
+	s.Labels = sortCounts(labels)
+	s.Lists = sortCounts(lists)
+	return s
+}
+
+// Streaks returns the current and longest runs of consecutive true days.
+// The current streak ends on the last day; with lastInProgress set an
+// unfinished last day does not break it yet.
+func Streaks(done []bool, lastInProgress bool) (current, longest int) {
+	run := 0
+	for _, d := range done {
+		if d {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	end := len(done) - 1
+	if lastInProgress && end >= 0 && !done[end] {
+		end--
+	}
+	for i := end; i >= 0 && done[i]; i-- {
+		current++
+	}
+	return current, longest
+}
+
+func count(m map[string]*NameCount, name string) *NameCount {
+	c, ok := m[name]
+	if !ok {
+		c = &NameCount{Name: name}
+		m[name] = c
+	}
+	return c
+}
+
+// sortCounts orders names by completed tasks, busiest first
+func sortCounts(m map[string]*NameCount) []NameCount {
+	result := make([]NameCount, 0, len(m))
+	for _, c := range m {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Completed != result[j].Completed {
+			return result[i].Completed > result[j].Completed
+		}
+		if result[i].Planned != result[j].Planned {
+			return result[i].Planned > result[j].Planned
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func TestNewStats(t *testing.T) {
+	dates := []string{"2024-01-14", "2024-01-15", "2024-01-16", "2024-01-17"}
+
+	a := task("a", "2024-01-14", 0, 0, true)
+	a.Priority = intPtr(4)
+	a.Label = strPtr("label-1")
+	b := task("b", "2024-01-15", 0, 0, true)
+	b.Priority = intPtr(2)
+	b.Label = strPtr("label-1")
+	// Planned on the 15th but finished the next day
+	c := task("c", "2024-01-15", 0, 0, true)
+	c.CompletedAt = json.RawMessage(`"2024-01-16T10:00:00Z"`)
+	tasks := []models.Task{a, b, c, task("d", "2024-01-16", 0, 0, false), task("e", "2024-01-17", 0, 0, false)}
+
+	s := NewStats(dates, "2024-01-17", tasks, testNames, time.UTC)
+
+	if s.Planned != 5 || s.Completed != 3 || s.CompletionRate != 0.6 {
+		t.Errorf("unexpected totals %d/%d %v", s.Completed, s.Planned, s.CompletionRate)
+	}
+	if s.AveragePriority != 3 {
+		t.Errorf("expected average priority 3, got %v", s.AveragePriority)
+	}
+
+	completed := []int{1, 1, 1, 0}
+	for i, d := range s.Days {
+		if d.Completed != completed[i] {
+			t.Errorf("%s: expected %d completed, got %d", d.Date, completed[i], d.Completed)
+		}
+	}
+	if s.Days[1].Planned != 2 {
+		t.Errorf("expected 2 tasks planned on the 15th, got %d", s.Days[1].Planned)
+	}
+
+	// The 14th is a Sunday, so the range spans two weeks
+	if len(s.Weeks) != 2 || s.Weeks[0].Date != "2024-01-08" || s.Weeks[1].Completed != 2 {
+		t.Errorf("unexpected weeks %+v", s.Weeks)
+	}
+
+	if s.CurrentStreak != 3 || s.LongestStreak != 3 {
+		t.Errorf("unexpected streaks %d/%d", s.CurrentStreak, s.LongestStreak)
+	}
+	if s.Labels[0].Name != "Deep Work" || s.Labels[0].Completed != 2 {
+		t.Errorf("unexpected labels %+v", s.Labels)
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	tests := []struct {
+		done             []bool
+		inProgress       bool
+		current, longest int
+	}{
+		{[]bool{true, true, false, true}, false, 1, 2},
+		{[]bool{true, true, true, false}, false, 0, 3},
+		{[]bool{true, true, true, false}, true, 3, 3},
+		{[]bool{false, false}, true, 0, 0},
+		{nil, true, 0, 0},
+	}
+	for _, tt := range tests {
+		current, longest := Streaks(tt.done, tt.inProgress)
+		if current != tt.current || longest != tt.longest {
+			t.Errorf("Streaks(%v, %v) = %d, %d, want %d, %d", tt.done, tt.inProgress, current, longest, tt.current, tt.longest)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// Grouping selects how timesheet entries are grouped
+type Grouping string
+
+const (
+	ByLabel Grouping = "label"
+	ByList  Grouping = "list"
+	ByDay   Grouping = "day"
+)
+
+// ParseGrouping validates a --by flag value
+func ParseGrouping(s string) (Grouping, error) {
+	switch g := Grouping(s); g {
+	case ByLabel, ByList, ByDay:
+		return g, nil
+	}
+	return "", fmt.Errorf("invalid grouping %q, use label, list or day", s)
+}
+
+// TimesheetEntry is the time booked on one task. Estimated is set when the
+// task has no tracked time and its estimate was used instead.
+type TimesheetEntry struct {
+	TaskID      string `json:"task_id"`
+	Description string `json:"description"`
+	Date        string `json:"date"`
+	Seconds     int    `json:"seconds"`
+	Estimated   bool   `json:"estimated,omitempty"`
+}
+
+// TimesheetGroup is the time booked on one label, list or day
+type TimesheetGroup struct {
+	Name             string           `json:"name"`
+	Seconds          int              `json:"seconds"`
+	EstimatedSeconds int              `json:"estimated_seconds"`
+	Entries          []TimesheetEntry `json:"entries"`
+}
+
+// Timesheet aggregates tracked time over a date range
+type Timesheet struct {
+	From             string           `json:"from"`
+	To               string           `json:"to"`
+	By               Grouping         `json:"by"`
+	Seconds          int              `json:"seconds"`
+	EstimatedSeconds int              `json:"estimated_seconds"`
+	Groups           []TimesheetGroup `json:"groups"`
+}
+
+// NewTimesheet books the actual time of every task in the range. Completed
+// tasks without tracked time fall back to their estimate, marked as such;
+// other tasks without tracked time are left out. Days are listed in order,
+// labels and lists by time booked.
+func NewTimesheet(from, to string, by Grouping, tasks []models.Task, names models.Names) *Timesheet {
+	ts := &Timesheet{From: from, To: to, By: by, Groups: []TimesheetGroup{}}
+	index := make(map[string]int)
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		entry := TimesheetEntry{
+			TaskID:      task.ID,
+			Description: task.Description,
+			Date:        task.GetDateString(),
+		}
+		switch {
+		case task.ActualTime != nil && *task.ActualTime > 0:
+			entry.Seconds = *task.ActualTime
+		case task.Complete && task.EstimatedTime != nil && *task.EstimatedTime > 0:
+			entry.Seconds = *task.EstimatedTime
+			entry.Estimated = true
+		default:
+			continue
+		}
+
+		var name string
+		switch by {
+		case ByLabel:
+			name = LabelName(task, names)
+		case ByList:
+			name = ListTitle(task, names)
+		default:
+			name = entry.Date
+		}
+
+		n, ok := index[name]
+		if !ok {
+			n = len(ts.Groups)
+			index[name] = n
+			ts.Groups = append(ts.Groups, TimesheetGroup{Name: name})
+		}
+		g := &ts.Groups[n]
+		g.Entries = append(g.Entries, entry)
+		g.Seconds += entry.Seconds
+		ts.Seconds += entry.Seconds
+		if entry.Estimated {
+			g.EstimatedSeconds += entry.Seconds
+			ts.EstimatedSeconds += entry.Seconds
+		}
+	}
+
+	sort.SliceStable(ts.Groups, func(i, j int) bool {
+		a, b := ts.Groups[i], ts.Groups[j]
+		if by == ByDay || a.Seconds == b.Seconds {
+			return a.Name < b.Name
+		}
+		return a.Seconds > b.Seconds
+	})
+	for _, g := range ts.Groups {
+		sort.SliceStable(g.Entries, func(i, j int) bool {
+			return g.Entries[i].Date < g.Entries[j].Date
+		})
+	}
+
+	return ts
+}
+
+// Hours converts seconds to hours rounded to two decimals, as billed
+func Hours(seconds int) float64 {
+	return round2(float64(seconds) / 3600)
+}
+
+// WriteCSV writes one row per entry with its group, suitable for
+// spreadsheets and invoicing tools
+func (ts *Timesheet) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{string(ts.By), "date", "task_id", "description", "hours", "seconds", "estimated"}); err != nil {
+		return err
+	}
+	for _, g := range ts.Groups {
+		for _, e := range g.Entries {
+			record := []string{
+				g.Name,
+				e.Date,
+				e.TaskID,
+				e.Description,
+				strconv.FormatFloat(Hours(e.Seconds), 'f', 2, 64),
+				strconv.Itoa(e.Seconds),
+				strconv.FormatBool(e.Estimated),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func timesheetTasks() []models.Task {
+	a := task("a", "2024-01-15", 3600, 5400, true)
+	a.Label = strPtr("label-1")
+	b := task("b", "2024-01-16", 1800, 0, true)
+	b.Label = strPtr("label-1")
+	c := task("c", "2024-01-15", 0, 900, false)
+	return []models.Task{
+		a, b, c,
+		task("d", "2024-01-16", 1800, 0, false),
+		task("e", "2024-01-16", 0, 0, true),
+	}
+}
+
+func TestTimesheetByLabel(t *testing.T) {
+	ts := NewTimesheet("2024-01-15", "2024-01-16", ByLabel, timesheetTasks(), testNames)
+
+	if ts.Seconds != 8100 || ts.EstimatedSeconds != 1800 {
+		t.Errorf("unexpected totals %d/%d", ts.Seconds, ts.EstimatedSeconds)
+	}
+	if len(ts.Groups) != 2 || ts.Groups[0].Name != "Deep Work" || ts.Groups[1].Name != NoLabel {
+		t.Fatalf("unexpected groups %+v", ts.Groups)
+	}
+	g := ts.Groups[0]
+	if g.Seconds != 7200 || g.EstimatedSeconds != 1800 || len(g.Entries) != 2 || !g.Entries[1].Estimated {
+		t.Errorf("unexpected group %+v", g)
+	}
+}
+
+func TestTimesheetByDay(t *testing.T) {
+	ts := NewTimesheet("2024-01-15", "2024-01-16", ByDay, timesheetTasks(), testNames)
+
+	if len(ts.Groups) != 2 || ts.Groups[0].Name != "2024-01-15" || ts.Groups[0].Seconds != 6300 {
+		t.Errorf("unexpected groups %+v", ts.Groups)
+	}
+}
+
+func TestTimesheetCSV(t *testing.T) {
+	ts := NewTimesheet("2024-01-15", "2024-01-16", ByList, timesheetTasks(), testNames)
+
+	var buf bytes.Buffer
+	if err := ts.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got %q", buf.String())
+	}
+	if lines[0] != "list,date,task_id,description,hours,seconds,estimated" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if lines[1] != "(no list),2024-01-15,a,Task a,1.50,5400,false" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+}
+
+func TestParseGrouping(t *testing.T) {
+	if _, err := ParseGrouping("week"); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+	if g, err := ParseGrouping("list"); err != nil || g != ByList {
+		t.Errorf("unexpected result %v, %v", g, err)
+	}
+}
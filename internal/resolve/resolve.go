@@ -0,0 +1,63 @@
+// Package resolve maps list and label names given by the user to IDs.
+//
+// A value matches an ID exactly, or a name ignoring case and the
+// difference between spaces, dashes and underscores, so that "deep-work"
+// written as a todo.txt context or org tag finds the label "Deep Work".
+// A value matching more than one name is an error rather than an
+// arbitrary pick.
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// ErrNotFound is wrapped by the error for a value matching no name, to
+// tell it apart from an ambiguous one
+var ErrNotFound = errors.New("not found")
+
+// ListID resolves a list given by ID or title
+func ListID(names models.Names, nameOrID string) (string, error) {
+	return ID(names.Lists, "list", nameOrID)
+}
+
+// LabelID resolves a label given by ID or name
+func LabelID(names models.Names, nameOrID string) (string, error) {
+	return ID(names.Labels, "label", nameOrID)
+}
+
+// ID resolves nameOrID against names keyed by ID. kind describes what is
+// being resolved in error messages.
+func ID(names map[string]string, kind, nameOrID string) (string, error) {
+	if _, ok := names[nameOrID]; ok {
+		return nameOrID, nil
+	}
+
+	want := normalize(nameOrID)
+	var matches []string
+	for id, name := range names {
+		if normalize(name) == want {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%s %q %w", kind, nameOrID, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	sort.Strings(matches)
+	return "", fmt.Errorf("%s %q is ambiguous, use one of the IDs %s", kind, nameOrID, strings.Join(matches, ", "))
+}
+
+// normalize folds case and drops spaces, dashes and underscores
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), ""))
+}
@@ -0,0 +1,59 @@
+package resolve
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestID(t *testing.T) {
+	names := map[string]string{
+		"label-1": "Deep Work",
+		"label-2": "Errands",
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"label-2", "label-2"},
+		{"Deep Work", "label-1"},
+		{"deep work", "label-1"},
+		{"deep-work", "label-1"},
+		{"Deep_Work", "label-1"},
+		{"ERRANDS", "label-2"},
+	}
+
+	for _, tt := range tests {
+		got, err := ID(names, "label", tt.value)
+		if err != nil {
+			t.Errorf("ID(%q): unexpected error: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ID(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestID_NotFound(t *testing.T) {
+	_, err := ID(map[string]string{"list-1": "Clients"}, "list", "Inbox")
+	if !errors.Is(err, ErrNotFound) || err.Error() != `list "Inbox" not found` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestID_Ambiguous(t *testing.T) {
+	names := map[string]string{
+		"list-2": "deep-work",
+		"list-1": "Deep Work",
+	}
+
+	_, err := ID(names, "list", "deep work")
+	if err == nil {
+		t.Fatal("expected error for ambiguous name")
+	}
+	if !strings.Contains(err.Error(), "list-1, list-2") {
+		t.Errorf("expected sorted candidate IDs, got %v", err)
+	}
+}
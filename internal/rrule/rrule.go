@@ -0,0 +1,427 @@
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the base period of a rule
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxIterations bounds the periods searched for occurrences, so that a
+// rule whose filters never match cannot loop forever
+const maxIterations = 100000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// shortcuts are friendly names accepted in place of a full rule
+var shortcuts = map[string]string{
+	"daily":    "FREQ=DAILY",
+	"weekdays": "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+	"weekly":   "FREQ=WEEKLY",
+	"biweekly": "FREQ=WEEKLY;INTERVAL=2",
+	"monthly":  "FREQ=MONTHLY",
+	"yearly":   "FREQ=YEARLY",
+}
+
+// Day is a BYDAY entry. N selects the nth weekday of the month (negative
+// counts from the end); zero means every such weekday.
+type Day struct {
+	Weekday time.Weekday
+	N       int
+}
+
+func (d Day) String() string {
+	if d.N == 0 {
+		return weekdayCodes[d.Weekday]
+	}
+	return strconv.Itoa(d.N) + weekdayCodes[d.Weekday]
+}
+
+// Rule is the subset of RFC 5545 recurrence rules that date-based tasks
+// need: FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL. Weeks start on
+// Monday.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []Day
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=MO,WE", with or without
+// an "RRULE:" prefix, or one of the shortcuts daily, weekdays, weekly,
+// biweekly, monthly and yearly
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	if expanded, ok := shortcuts[strings.ToLower(s)]; ok {
+		s = expanded
+	}
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch key {
+		case "FREQ":
+			switch f := Frequency(value); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid interval %q", value)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid count %q", value)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, err := parseDay(code)
+				if err != nil {
+					return nil, err
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid month day %q", v)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "WKST":
+			if value != "MO" {
+				return nil, fmt.Errorf("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("recurrence rule needs FREQ")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly {
+			return nil, fmt.Errorf("numbered BYDAY such as %s needs FREQ=MONTHLY", d)
+		}
+	}
+	return r, nil
+}
+
+func parseDay(code string) (Day, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return Day{}, fmt.Errorf("invalid weekday %q", code)
+	}
+	wd, ok := weekdays[code[len(code)-2:]]
+	if !ok {
+		return Day{}, fmt.Errorf("invalid weekday %q", code)
+	}
+	day := Day{Weekday: wd}
+	if prefix := code[:len(code)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return Day{}, fmt.Errorf("invalid weekday %q", code)
+		}
+		day.N = n
+	}
+	return day, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL date %q", value)
+}
+
+// String returns the rule in canonical RRULE form, without the prefix
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of a series starting on start that fall
+// within [from, to], at most limit of them when limit is positive. Dates
+// are compared as calendar days; times of day are ignored. COUNT is
+// counted from start, so occurrences before from still use it up.
+func (r *Rule) Between(start, from, to time.Time, limit int) []time.Time {
+	start, from, to = day(start), day(from), day(to)
+
+	var result []time.Time
+	count := 0
+	for period := 0; period < maxIterations; period++ {
+		candidates := r.period(start, period)
+		if len(candidates) == 0 && r.periodStart(start, period).After(to) {
+			break
+		}
+
+		for _, d := range candidates {
+			if d.Before(start) {
+				continue
+			}
+			if (!r.Until.IsZero() && d.After(r.Until)) || d.After(to) {
+				return result
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return result
+			}
+			if !d.Before(from) {
+				result = append(result, d)
+				if limit > 0 && len(result) >= limit {
+					return result
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Occurs reports whether the series starting on start has an occurrence on date
+func (r *Rule) Occurs(start, date time.Time) bool {
+	return len(r.Between(start, date, date, 1)) == 1
+}
+
+// periodStart returns the first day of the nth period of the series
+func (r *Rule) periodStart(start time.Time, n int) time.Time {
+	step := n * r.Interval
+	switch r.Freq {
+	case Daily:
+		return start.AddDate(0, 0, step)
+	case Weekly:
+		monday := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		return monday.AddDate(0, 0, 7*step)
+	case Monthly:
+		return time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(start.Year()+step, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// period returns the sorted candidate days of the nth period
+func (r *Rule) period(start time.Time, n int) []time.Time {
+	first := r.periodStart(start, n)
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		if r.matchesFilters(first) {
+			days = append(days, first)
+		}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			days = append(days, first.AddDate(0, 0, (int(start.Weekday())+6)%7))
+			break
+		}
+		for i := 0; i < 7; i++ {
+			d := first.AddDate(0, 0, i)
+			if r.matchesWeekday(d) && r.matchesMonthDay(d) {
+				days = append(days, d)
+			}
+		}
+	case Monthly:
+		days = r.monthDays(first, start)
+	default:
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			d := time.Date(first.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+			if d.Day() == start.Day() {
+				days = append(days, d)
+			}
+			break
+		}
+		// BYDAY and BYMONTHDAY apply within the month of the first occurrence
+		days = r.monthDays(time.Date(first.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC), start)
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// monthDays returns the matching days of the month beginning on first
+func (r *Rule) monthDays(first, start time.Time) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+
+	var days []time.Time
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		if start.Day() <= last {
+			days = append(days, first.AddDate(0, 0, start.Day()-1))
+		}
+		return days
+	}
+
+	for dom := 1; dom <= last; dom++ {
+		d := first.AddDate(0, 0, dom-1)
+		if r.matchesFilters(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+func (r *Rule) matchesFilters(d time.Time) bool {
+	return r.matchesWeekday(d) && r.matchesMonthDay(d)
+}
+
+func (r *Rule) matchesWeekday(d time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, bd := range r.ByDay {
+		if bd.Weekday != d.Weekday() {
+			continue
+		}
+		switch {
+		case bd.N == 0:
+			return true
+		case bd.N > 0 && (d.Day()-1)/7+1 == bd.N:
+			return true
+		case bd.N < 0 && (last-d.Day())/7+1 == -bd.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, n := range r.ByMonthDay {
+		if n == d.Day() || (n < 0 && last+n+1 == d.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe returns a short human description, e.g. "every 2 weeks on Mon, Wed"
+func (r *Rule) Describe() string {
+	units := map[Frequency]string{Daily: "day", Weekly: "week", Monthly: "month", Yearly: "year"}
+	s := "every " + units[r.Freq]
+	if r.Interval > 1 {
+		s = fmt.Sprintf("every %d %ss", r.Interval, units[r.Freq])
+	}
+
+	if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			name := d.Weekday.String()[:3]
+			switch {
+			case d.N == -1:
+				name = "last " + name
+			case d.N < 0:
+				name = fmt.Sprintf("%s from last %s", ordinal(-d.N), name)
+			case d.N > 0:
+				name = ordinal(d.N) + " " + name
+			}
+			names[i] = name
+		}
+		s += " on " + strings.Join(names, ", ")
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			if d < 0 {
+				days[i] = fmt.Sprintf("%d from the end", -d)
+			} else {
+				days[i] = ordinal(d)
+			}
+		}
+		s += " on the " + strings.Join(days, ", ")
+	}
+	if r.Count > 0 {
+		s += fmt.Sprintf(", %d times", r.Count)
+	}
+	if !r.Until.IsZero() {
+		s += ", until " + r.Until.Format("2006-01-02")
+	}
+	return s
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
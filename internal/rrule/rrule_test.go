@@ -0,0 +1,138 @@
+package rrule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dates(ts []time.Time) string {
+	s := make([]string, len(ts))
+	for i, t := range ts {
+		s[i] = t.Format("2006-01-02")
+	}
+	return strings.Join(s, ",")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;INTERVAL=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{"freq=monthly;byday=-1fr", "FREQ=MONTHLY;BYDAY=-1FR"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=3", "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=3"},
+		{"FREQ=DAILY;UNTIL=20240131T000000Z", "FREQ=DAILY;UNTIL=20240131"},
+		{"weekdays", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if r.String() != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, r.String(), tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;BYSETPOS=1",
+	} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		rule  string
+		start string
+		from  string
+		to    string
+		want  string
+	}{
+		{"FREQ=DAILY;INTERVAL=2", "2024-01-01", "2024-01-01", "2024-01-07", "2024-01-01,2024-01-03,2024-01-05,2024-01-07"},
+		{"FREQ=DAILY;COUNT=3", "2024-01-01", "2024-01-02", "2024-01-31", "2024-01-02,2024-01-03"},
+		{"FREQ=DAILY;UNTIL=20240103", "2024-01-01", "2024-01-01", "2024-01-31", "2024-01-01,2024-01-02,2024-01-03"},
+		// 2024-01-03 is a Wednesday; the Monday of that week is skipped
+		{"FREQ=WEEKLY;BYDAY=MO,WE", "2024-01-03", "2024-01-01", "2024-01-15", "2024-01-03,2024-01-08,2024-01-10,2024-01-15"},
+		{"FREQ=WEEKLY;INTERVAL=2", "2024-01-03", "2024-01-01", "2024-01-31", "2024-01-03,2024-01-17,2024-01-31"},
+		{"weekdays", "2024-01-05", "2024-01-05", "2024-01-09", "2024-01-05,2024-01-08,2024-01-09"},
+		{"FREQ=MONTHLY", "2024-01-31", "2024-01-01", "2024-05-31", "2024-01-31,2024-03-31,2024-05-31"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2024-01-01", "2024-01-01", "2024-03-31", "2024-01-26,2024-02-23,2024-03-29"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "2024-01-01", "2024-01-01", "2024-02-29", "2024-01-09,2024-02-13"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "2024-01-15", "2024-01-01", "2024-03-31", "2024-01-31,2024-02-29,2024-03-31"},
+		{"FREQ=YEARLY", "2024-02-29", "2024-01-01", "2028-12-31", "2024-02-29,2028-02-29"},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.rule, err)
+		}
+		got := dates(r.Between(date(tt.start), date(tt.from), date(tt.to), 0))
+		if got != tt.want {
+			t.Errorf("%s from %s: got %s, want %s", tt.rule, tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestBetweenLimit(t *testing.T) {
+	r, _ := Parse("daily")
+	got := r.Between(date("2024-01-01"), date("2024-01-10"), date("2030-01-01"), 2)
+	if dates(got) != "2024-01-10,2024-01-11" {
+		t.Errorf("unexpected occurrences %s", dates(got))
+	}
+}
+
+func TestBetweenNeverMatching(t *testing.T) {
+	r, _ := Parse("FREQ=MONTHLY;BYMONTHDAY=31;BYDAY=MO")
+	got := r.Between(date("2024-01-01"), date("2024-01-01"), date("2024-03-01"), 0)
+	if len(got) != 0 {
+		t.Errorf("expected no occurrences, got %s", dates(got))
+	}
+}
+
+func TestOccurs(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;BYDAY=MO")
+	if !r.Occurs(date("2024-01-01"), date("2024-01-15")) {
+		t.Error("expected an occurrence on a Monday")
+	}
+	if r.Occurs(date("2024-01-01"), date("2024-01-16")) {
+		t.Error("expected no occurrence on a Tuesday")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := map[string]string{
+		"FREQ=DAILY":                         "every day",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE": "every 2 weeks on Mon, Wed",
+		"FREQ=MONTHLY;BYDAY=-1FR":            "every month on last Fri",
+		"FREQ=MONTHLY;BYMONTHDAY=1;COUNT=6":  "every month on the 1st, 6 times",
+	}
+	for rule, want := range tests {
+		r, _ := Parse(rule)
+		if got := r.Describe(); got != want {
+			t.Errorf("Describe(%s) = %q, want %q", rule, got, want)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package taskcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+	"gopkg.in/yaml.v3"
+)
+
+// Task fields that can be mapped to CSV columns
+const (
+	FieldID          = "id"
+	FieldDescription = "description"
+	FieldDate        = "date"
+	FieldStart       = "start"
+	FieldDueDate     = "due_date"
+	FieldEstimate    = "estimated_minutes"
+	FieldList        = "list"
+	FieldLabel       = "label"
+	FieldPriority    = "priority"
+	FieldComplete    = "complete"
+)
+
+// Fields is the column order used for exports. Importing an export with
+// the default mapping reproduces the tasks.
+var Fields = []string{
+	FieldID,
+	FieldDescription,
+	FieldDate,
+	FieldStart,
+	FieldDueDate,
+	FieldEstimate,
+	FieldList,
+	FieldLabel,
+	FieldPriority,
+	FieldComplete,
+}
+
+// Mapping maps task fields to CSV header names
+type Mapping map[string]string
+
+// DefaultMapping maps every field to a column of the same name
+func DefaultMapping() Mapping {
+	m := make(Mapping, len(Fields))
+	for _, field := range Fields {
+		m[field] = field
+	}
+	return m
+}
+
+// LoadMapping reads a field-to-header mapping from a YAML or JSON file
+func LoadMapping(path string) (Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	var m Mapping
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file: %w", err)
+	}
+	return m, m.Validate()
+}
+
+// ParseMappingFlags parses field=header pairs
+func ParseMappingFlags(pairs []string) (Mapping, error) {
+	m := make(Mapping)
+	for _, pair := range pairs {
+		field, header, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(field) == "" || strings.TrimSpace(header) == "" {
+			return nil, fmt.Errorf("invalid mapping %q: expected field=header", pair)
+		}
+		m[strings.TrimSpace(field)] = strings.TrimSpace(header)
+	}
+	return m, m.Validate()
+}
+
+// Validate checks that every mapped field is known
+func (m Mapping) Validate() error {
+	for field := range m {
+		known := false
+		for _, f := range Fields {
+			if f == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown field %q (valid fields: %s)", field, strings.Join(Fields, ", "))
+		}
+	}
+	return nil
+}
+
+// Merge returns a mapping where entries from other override m
+func (m Mapping) Merge(other Mapping) Mapping {
+	merged := make(Mapping, len(m)+len(other))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Options controls how rows are parsed and written
+type Options struct {
+	Mapping Mapping
+	Names   models.Names
+	// DateLayout is the Go layout of date columns, defaulting to YYYY-MM-DD
+	DateLayout string
+	// Location is the zone exported dates and start times are written in,
+	// defaulting to the local zone
+	Location *time.Location
+}
+
+func (o *Options) defaults() {
+	if o.DateLayout == "" {
+		o.DateLayout = dateutil.Layout
+	}
+	if o.Mapping == nil {
+		o.Mapping = DefaultMapping()
+	}
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+}
+
+// Row is a parsed CSV row. ID is the task the row was exported from, if
+// the file has an id column.
+type Row struct {
+	Line     int                       `json:"line"`
+	ID       string                    `json:"id,omitempty"`
+	Request  *models.CreateTaskRequest `json:"request"`
+	Complete bool                      `json:"complete"`
+}
+
+// RowError describes a validation failure in a single row
+type RowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+}
+
+// Read parses and validates every row. Rows with errors are left out of
+// the result and reported instead; err is only set when the file itself
+// cannot be read.
+func Read(r io.Reader, opts Options) ([]Row, []RowError, error) {
+	opts.defaults()
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for field, name := range opts.Mapping {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), name) {
+				columns[field] = i
+				break
+			}
+		}
+	}
+	if _, ok := columns[FieldDescription]; !ok {
+		return nil, nil, fmt.Errorf("no column %q for the description field", opts.Mapping[FieldDescription])
+	}
+
+	var rows []Row
+	var rowErrors []RowError
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, RowError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		row, errs := parseRow(line, get, &opts)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseRow(line int, get func(string) string, opts *Options) (Row, []RowError) {
+	row := Row{Line: line, Request: &models.CreateTaskRequest{}}
+	var errs []RowError
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, RowError{Line: line, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	row.ID = get(FieldID)
+	req := row.Request
+	req.Description = get(FieldDescription)
+	if req.Description == "" {
+		fail(FieldDescription, "is required")
+	}
+
+	if v := get(FieldDate); v != "" {
+		if date, err := time.Parse(opts.DateLayout, v); err != nil {
+			fail(FieldDate, "invalid date %q (expected layout %s)", v, opts.DateLayout)
+		} else {
+			s := date.Format(dateutil.Layout)
+			req.Date = &s
+		}
+	}
+	if v := get(FieldStart); v != "" {
+		if _, err := time.Parse("15:04", v); err != nil {
+			fail(FieldStart, "invalid time %q (expected HH:MM)", v)
+		} else {
+			req.Start = &v
+		}
+	}
+	if v := get(FieldDueDate); v != "" {
+		if date, err := time.Parse(opts.DateLayout, v); err != nil {
+			fail(FieldDueDate, "invalid date %q (expected layout %s)", v, opts.DateLayout)
+		} else {
+			s := date.Format(dateutil.Layout)
+			req.DueDate = &s
+		}
+	}
+	if v := get(FieldEstimate); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes < 0 {
+			fail(FieldEstimate, "invalid number of minutes %q", v)
+		} else if minutes > 0 {
+			seconds := minutes * 60
+			req.EstimatedTime = &seconds
+		}
+	}
+	if v := get(FieldList); v != "" {
+		if id, err := resolve.ListID(opts.Names, v); err == nil {
+			req.ListID = &id
+		} else {
+			fail(FieldList, "%v", err)
+		}
+	}
+	if v := get(FieldLabel); v != "" {
+		if id, err := resolve.LabelID(opts.Names, v); err == nil {
+			req.Label = &id
+		} else {
+			fail(FieldLabel, "%v", err)
+		}
+	}
+	if v := get(FieldPriority); v != "" {
+		if p, ok := parsePriority(v); ok {
+			req.Priority = &p
+		} else {
+			fail(FieldPriority, "invalid priority %q (expected 1-4, A-D or low/medium/high/urgent)", v)
+		}
+	}
+	if v := get(FieldComplete); v != "" {
+		if complete, ok := parseBool(v); ok {
+			row.Complete = complete
+		} else {
+			fail(FieldComplete, "invalid value %q (expected true/false, yes/no or x)", v)
+		}
+	}
+
+	return row, errs
+}
+
+// Write exports tasks using the default column layout. Dates and start
+// times are written in opts.Location in the forms Read accepts with the
+// same options. The mapping is not used.
+func Write(w io.Writer, tasks []models.Task, opts Options) error {
+	opts.defaults()
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Fields); err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		record := make([]string, len(Fields))
+		for j, field := range Fields {
+			record[j] = exportValue(task, field, &opts)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func exportValue(task *models.Task, field string, opts *Options) string {
+	switch field {
+	case FieldID:
+		return task.ID
+	case FieldDescription:
+		return task.Description
+	case FieldDate:
+		if date, ok := dateutil.ParseDay(task.GetDateString(), opts.Location); ok {
+			return date.Format(opts.DateLayout)
+		}
+	case FieldStart:
+		if start, ok := dateutil.ParseStart(task.GetDateString(), task.GetStartString(), opts.Location); ok {
+			return start.Format("15:04")
+		}
+	case FieldDueDate:
+		if due, ok := dateutil.ParseDay(task.GetDueDateString(), opts.Location); ok {
+			return due.Format(opts.DateLayout)
+		}
+	case FieldEstimate:
+		if task.EstimatedTime != nil && *task.EstimatedTime > 0 {
+			return strconv.Itoa(*task.EstimatedTime / 60)
+		}
+	case FieldList:
+		if task.ListID != nil {
+			if title, ok := opts.Names.Lists[*task.ListID]; ok {
+				return title
+			}
+			return *task.ListID
+		}
+	case FieldLabel:
+		if task.Label != nil {
+			if name, ok := opts.Names.Labels[*task.Label]; ok {
+				return name
+			}
+			return *task.Label
+		}
+	case FieldPriority:
+		if task.Priority != nil {
+			return strconv.Itoa(*task.Priority)
+		}
+	case FieldComplete:
+		return strconv.FormatBool(task.Complete)
+	}
+	return ""
+}
+
+func parsePriority(v string) (int, bool) {
+	if p, err := strconv.Atoi(v); err == nil {
+		return p, p >= 1 && p <= 4
+	}
+	switch strings.ToLower(v) {
+	case "low":
+		return 1, true
+	case "medium":
+		return 2, true
+	case "high":
+		return 3, true
+	case "urgent":
+		return 4, true
+	}
+	switch strings.ToUpper(v) {
+	case "A", "B", "C", "D":
+		return models.PriorityFromLetter(v), true
+	}
+	return 0, false
+}
+
+func parseBool(v string) (bool, bool) {
+	switch strings.ToLower(v) {
+	case "true", "yes", "y", "x", "1", "done":
+		return true, true
+	case "false", "no", "n", "0", "":
+		return false, true
+	}
+	return false, false
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package taskcsv
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var testNames = models.Names{
+	Lists:  map[string]string{"list-1": "Clients"},
+	Labels: map[string]string{"label-1": "Billable"},
+}
+
+func TestRoundTrip(t *testing.T) {
+	tasks := []models.Task{{
+		ID:            "task-1",
+		Description:   "Draft proposal, v2",
+		Date:          json.RawMessage(`"2024-01-15"`),
+		Start:         json.RawMessage(`"09:30"`),
+		DueDate:       json.RawMessage(`"2024-01-20"`),
+		EstimatedTime: intPtr(5400),
+		ListID:        strPtr("list-1"),
+		Label:         strPtr("label-1"),
+		Priority:      intPtr(3),
+		Complete:      true,
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, Options{Names: testNames, Location: time.UTC}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, rowErrors, err := Read(&buf, Options{Names: testNames})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rowErrors) > 0 {
+		t.Fatalf("unexpected row errors: %v", rowErrors)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+
+	req := rows[0].Request
+	if req.Description != "Draft proposal, v2" {
+		t.Errorf("unexpected description: %q", req.Description)
+	}
+	if *req.Date != "2024-01-15" || *req.Start != "09:30" || *req.DueDate != "2024-01-20" {
+		t.Errorf("unexpected schedule: %s %s %s", *req.Date, *req.Start, *req.DueDate)
+	}
+	if *req.EstimatedTime != 5400 {
+		t.Errorf("expected 5400 seconds, got %d", *req.EstimatedTime)
+	}
+	if *req.ListID != "list-1" || *req.Label != "label-1" {
+		t.Errorf("expected resolved IDs, got %s %s", *req.ListID, *req.Label)
+	}
+	if *req.Priority != 3 || !rows[0].Complete {
+		t.Errorf("unexpected priority/complete: %d %v", *req.Priority, rows[0].Complete)
+	}
+}
+
+func TestRead_CustomMapping(t *testing.T) {
+	input := "Task Name,Due,Owner Tag,Prio\n" +
+		"Review budget,01/31/2024,billable,High\n"
+
+	mapping, err := ParseMappingFlags([]string{"description=Task Name", "date=Due", "label=Owner Tag", "priority=Prio"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, rowErrors, err := Read(strings.NewReader(input), Options{
+		Mapping:    mapping,
+		Names:      testNames,
+		DateLayout: "01/02/2006",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rowErrors) > 0 {
+		t.Fatalf("unexpected row errors: %v", rowErrors)
+	}
+
+	req := rows[0].Request
+	if req.Description != "Review budget" || *req.Date != "2024-01-31" || *req.Label != "label-1" || *req.Priority != 3 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestRead_RowErrors(t *testing.T) {
+	input := "description,date,start,priority,list\n" +
+		"Good row,2024-01-15,10:00,2,Clients\n" +
+		",2024-13-01,25:00,9,Unknown\n"
+
+	rows, rowErrors, err := Read(strings.NewReader(input), Options{Names: testNames})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected 1 valid row, got %d", len(rows))
+	}
+	if len(rowErrors) != 5 {
+		t.Fatalf("expected 5 errors, got %d: %v", len(rowErrors), rowErrors)
+	}
+	for _, rowErr := range rowErrors {
+		if rowErr.Line != 3 {
+			t.Errorf("expected errors on line 3, got %d", rowErr.Line)
+		}
+	}
+}
+
+func TestRead_MalformedRow(t *testing.T) {
+	input := "description,date\n" +
+		"Good row,2024-01-15\n" +
+		"Bad \"quoted\" row,2024-01-15\n" +
+		"Another row,2024-01-16\n"
+
+	rows, rowErrors, err := Read(strings.NewReader(input), Options{Names: testNames})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("expected 2 valid rows, got %d", len(rows))
+	}
+	if len(rowErrors) != 1 || rowErrors[0].Line != 3 {
+		t.Fatalf("expected 1 error on line 3, got %v", rowErrors)
+	}
+}
+
+func TestWrite_StartAsClock(t *testing.T) {
+	tasks := []models.Task{{
+		ID:          "task-1",
+		Description: "Standup",
+		Date:        json.RawMessage(`"2024-01-15"`),
+		Start:       json.RawMessage(`"2024-01-15T08:30:00Z"`),
+	}}
+	loc := time.FixedZone("CET", 3600)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, Options{Names: testNames, Location: loc}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, rowErrors, err := Read(&buf, Options{Names: testNames})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rowErrors) > 0 {
+		t.Fatalf("unexpected row errors: %v", rowErrors)
+	}
+	if rows[0].ID != "task-1" {
+		t.Errorf("expected ID task-1, got %q", rows[0].ID)
+	}
+	if *rows[0].Request.Start != "09:30" {
+		t.Errorf("expected start 09:30, got %s", *rows[0].Request.Start)
+	}
+}
+
+func TestWrite_DatesInReadLayout(t *testing.T) {
+	tasks := []models.Task{{
+		ID:          "task-1",
+		Description: "Pay invoice",
+		Date:        json.RawMessage(`"2024-01-15T00:00:00Z"`),
+		DueDate:     json.RawMessage(`"2024-01-31T23:00:00Z"`),
+	}}
+	opts := Options{Names: testNames, DateLayout: "01/02/2006", Location: time.UTC}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, rowErrors, err := Read(&buf, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rowErrors) > 0 {
+		t.Fatalf("unexpected row errors: %v", rowErrors)
+	}
+	req := rows[0].Request
+	if *req.Date != "2024-01-15" || *req.DueDate != "2024-01-31" {
+		t.Errorf("unexpected dates %s %s", *req.Date, *req.DueDate)
+	}
+}
+
+func TestParseMappingFlags_UnknownField(t *testing.T) {
+	if _, err := ParseMappingFlags([]string{"owner=Owner"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	valid := map[string]int{"1": 1, "4": 4, "a": 4, "D": 1, "High": 3}
+	for v, expected := range valid {
+		if p, ok := parsePriority(v); !ok || p != expected {
+			t.Errorf("parsePriority(%q): expected %d, got %d %v", v, expected, p, ok)
+		}
+	}
+	for _, v := range []string{"0", "5", "E", "z", "later"} {
+		if _, ok := parsePriority(v); ok {
+			t.Errorf("parsePriority(%q): expected failure", v)
+		}
+	}
+}
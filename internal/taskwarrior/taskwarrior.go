@@ -0,0 +1,260 @@
+package taskwarrior
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+)
+
+// TimeFormat is the timestamp format used by Taskwarrior's JSON
+const TimeFormat = "20060102T150405Z"
+
+// Task statuses
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusDeleted   = "deleted"
+	StatusWaiting   = "waiting"
+	StatusRecurring = "recurring"
+)
+
+// PriorityToEllie maps Taskwarrior priorities onto Ellie's 1-4 scale
+var PriorityToEllie = map[string]int{
+	"H": 3,
+	"M": 2,
+	"L": 1,
+}
+
+// PriorityFromEllie maps Ellie priorities onto Taskwarrior's H/M/L.
+// Taskwarrior has no level above H, so urgent tasks export as H.
+var PriorityFromEllie = map[int]string{
+	4: "H",
+	3: "H",
+	2: "M",
+	1: "L",
+}
+
+// Annotation is a timestamped note attached to a task
+type Annotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// Task is a task in Taskwarrior's import/export JSON format
+type Task struct {
+	UUID        string       `json:"uuid"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"`
+	Entry       string       `json:"entry"`
+	Modified    string       `json:"modified,omitempty"`
+	End         string       `json:"end,omitempty"`
+	Due         string       `json:"due,omitempty"`
+	Scheduled   string       `json:"scheduled,omitempty"`
+	Priority    string       `json:"priority,omitempty"`
+	Project     string       `json:"project,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Read decodes the output of `task export`, which is a JSON array in
+// current versions and one object per line in older ones
+func Read(r io.Reader) ([]Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Taskwarrior export: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var tasks []Task
+		if err := json.Unmarshal(trimmed, &tasks); err != nil {
+			return nil, fmt.Errorf("failed to parse Taskwarrior export: %w", err)
+		}
+		return tasks, nil
+	}
+
+	var tasks []Task
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ",")
+		if line == "" {
+			continue
+		}
+		var task Task
+		if err := json.Unmarshal([]byte(line), &task); err != nil {
+			return nil, fmt.Errorf("failed to parse Taskwarrior export line %d: %w", lineNo, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// Write encodes tasks as a JSON array accepted by `task import`
+func Write(w io.Writer, tasks []Task) error {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tasks)
+}
+
+// Importable reports whether a task should become an Ellie task. Deleted
+// tasks and recurrence templates are skipped.
+func (t *Task) Importable() bool {
+	return t.Status != StatusDeleted && t.Status != StatusRecurring
+}
+
+// CreateRequest maps a Taskwarrior task onto an Ellie task:
+//
+//	description  -> description, with annotations appended after " — "
+//	scheduled    -> date, and start unless it is midnight
+//	due          -> due date
+//	priority     -> priority via PriorityToEllie
+//	project      -> list, matched by name (then by its top-level part)
+//	tags         -> label, the first tag that matches a label name
+//	status       -> complete when "completed"
+func (t *Task) CreateRequest(names models.Names, loc *time.Location) (*models.CreateTaskRequest, []string) {
+	req := &models.CreateTaskRequest{Description: t.Description}
+	var warnings []string
+
+	var notes []string
+	for _, annotation := range t.Annotations {
+		if note := strings.TrimSpace(annotation.Description); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	if len(notes) > 0 {
+		req.Description += " — " + strings.Join(notes, "; ")
+	}
+
+	if t.Scheduled != "" {
+		if scheduled, err := time.Parse(TimeFormat, t.Scheduled); err == nil {
+			scheduled = scheduled.In(loc)
+			date := dateutil.Format(scheduled)
+			req.Date = &date
+			if !scheduled.Equal(dateutil.Truncate(scheduled)) {
+				start := scheduled.Format("15:04")
+				req.Start = &start
+			}
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid scheduled date %q", t.Scheduled))
+		}
+	}
+	if t.Due != "" {
+		if due, err := time.Parse(TimeFormat, t.Due); err == nil {
+			date := dateutil.Format(due.In(loc))
+			req.DueDate = &date
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid due date %q", t.Due))
+		}
+	}
+
+	if t.Priority != "" {
+		if p, ok := PriorityToEllie[strings.ToUpper(t.Priority)]; ok {
+			req.Priority = &p
+		} else {
+			warnings = append(warnings, fmt.Sprintf("unknown priority %q", t.Priority))
+		}
+	}
+
+	if t.Project != "" {
+		id, err := resolve.ListID(names, t.Project)
+		if top, _, nested := strings.Cut(t.Project, "."); err != nil && nested {
+			id, err = resolve.ListID(names, top)
+		}
+		if err == nil {
+			req.ListID = &id
+		} else {
+			warnings = append(warnings, fmt.Sprintf("project %q ignored: %v", t.Project, err))
+		}
+	}
+
+	for _, tag := range t.Tags {
+		id, err := resolve.LabelID(names, tag)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("tag +%s ignored: %v", tag, err))
+		case req.Label == nil:
+			req.Label = &id
+		default:
+			warnings = append(warnings, fmt.Sprintf("tag +%s ignored, tasks have a single label", tag))
+		}
+	}
+
+	return req, warnings
+}
+
+// FromTask maps an Ellie task onto Taskwarrior. The UUID is derived from
+// the Ellie ID, so exporting the same task twice updates it on import
+// instead of duplicating it.
+func FromTask(task *models.Task, names models.Names, loc *time.Location, now time.Time) Task {
+	t := Task{
+		UUID:        UUID(task.ID),
+		Description: strings.Join(strings.Fields(task.Description), " "),
+		Status:      StatusPending,
+		Entry:       now.UTC().Format(TimeFormat),
+	}
+
+	if created, err := time.Parse(time.RFC3339, task.GetCreatedAtString()); err == nil {
+		t.Entry = created.UTC().Format(TimeFormat)
+	}
+	if task.Complete {
+		t.Status = StatusCompleted
+		t.End = now.UTC().Format(TimeFormat)
+		if completed, err := time.Parse(time.RFC3339, task.GetCompletedAtString()); err == nil {
+			t.End = completed.UTC().Format(TimeFormat)
+		}
+	}
+
+	if date := task.GetDateString(); date != "" {
+		if start, ok := dateutil.ParseStart(date, task.GetStartString(), loc); ok {
+			t.Scheduled = start.UTC().Format(TimeFormat)
+		} else if day, ok := dateutil.ParseDay(date, loc); ok {
+			t.Scheduled = day.UTC().Format(TimeFormat)
+		}
+	}
+	if due, ok := dateutil.ParseDay(task.GetDueDateString(), loc); ok {
+		t.Due = due.UTC().Format(TimeFormat)
+	}
+
+	if task.Priority != nil {
+		t.Priority = PriorityFromEllie[*task.Priority]
+	}
+	if task.ListID != nil {
+		if title, ok := names.Lists[*task.ListID]; ok {
+			t.Project = strings.Join(strings.Fields(title), "_")
+		}
+	}
+	if task.Label != nil {
+		if name, ok := names.Labels[*task.Label]; ok {
+			t.Tags = []string{strings.Join(strings.Fields(name), "_")}
+		}
+	}
+
+	return t
+}
+
+// UUID derives a stable name-based (version 5 style) UUID from an Ellie task ID
+func UUID(taskID string) string {
+	h := sha1.New()
+	h.Write([]byte("ellieplanner.com/tasks/"))
+	h.Write([]byte(taskID))
+	sum := h.Sum(nil)
+
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
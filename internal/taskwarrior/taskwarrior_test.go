@@ -0,0 +1,211 @@
+package taskwarrior
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var testNames = models.Names{
+	Lists:  map[string]string{"list-1": "Clients"},
+	Labels: map[string]string{"label-1": "Deep Work"},
+}
+
+const sampleExport = `[
+{"id":1,"description":"Write report","entry":"20240110T080000Z","modified":"20240110T080000Z","scheduled":"20240115T090000Z","due":"20240120T000000Z","priority":"H","project":"Clients.Acme","tags":["Deep_Work","later"],"status":"pending","uuid":"2b1c6c4e-1111-4b7a-9a55-0d6b8c1f0a01","annotations":[{"entry":"20240111T100000Z","description":"see email"}],"urgency":9.1},
+{"id":0,"description":"Old chore","end":"20240105T120000Z","entry":"20240101T080000Z","status":"completed","uuid":"2b1c6c4e-2222-4b7a-9a55-0d6b8c1f0a02"},
+{"id":0,"description":"Gone","entry":"20240101T080000Z","status":"deleted","uuid":"2b1c6c4e-3333-4b7a-9a55-0d6b8c1f0a03"}
+]`
+
+func TestReadArray(t *testing.T) {
+	tasks, err := Read(strings.NewReader(sampleExport))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Project != "Clients.Acme" || len(tasks[0].Annotations) != 1 {
+		t.Errorf("unexpected first task: %+v", tasks[0])
+	}
+	if tasks[2].Importable() {
+		t.Error("deleted task should not be importable")
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	input := `{"description":"One","status":"pending","uuid":"a"},
+{"description":"Two","status":"pending","uuid":"b"}
+`
+	tasks, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[1].Description != "Two" {
+		t.Errorf("unexpected tasks: %+v", tasks)
+	}
+}
+
+func TestCreateRequest(t *testing.T) {
+	tasks, err := Read(strings.NewReader(sampleExport))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	req, warnings := tasks[0].CreateRequest(testNames, time.UTC)
+	if req.Description != "Write report — see email" {
+		t.Errorf("unexpected description %q", req.Description)
+	}
+	if req.Date == nil || *req.Date != "2024-01-15" {
+		t.Errorf("unexpected date %v", req.Date)
+	}
+	if req.Start == nil || *req.Start != "09:00" {
+		t.Errorf("unexpected start %v", req.Start)
+	}
+	if req.DueDate == nil || *req.DueDate != "2024-01-20" {
+		t.Errorf("unexpected due date %v", req.DueDate)
+	}
+	if req.Priority == nil || *req.Priority != 3 {
+		t.Errorf("unexpected priority %v", req.Priority)
+	}
+	if req.ListID == nil || *req.ListID != "list-1" {
+		t.Errorf("expected project to fall back to its top-level list, got %v", req.ListID)
+	}
+	if req.Label == nil || *req.Label != "label-1" {
+		t.Errorf("unexpected label %v", req.Label)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "+later") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	req, _ = tasks[1].CreateRequest(testNames, time.UTC)
+	if req.Date != nil || req.Start != nil || req.Priority != nil {
+		t.Errorf("expected an undated task without priority, got %+v", req)
+	}
+}
+
+func TestCreateRequestMidnightHasNoStart(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	task := Task{Description: "All day", Status: StatusPending, Scheduled: "20240114T230000Z"}
+
+	req, _ := task.CreateRequest(testNames, loc)
+	if req.Date == nil || *req.Date != "2024-01-15" {
+		t.Errorf("unexpected date %v", req.Date)
+	}
+	if req.Start != nil {
+		t.Errorf("expected no start at local midnight, got %q", *req.Start)
+	}
+}
+
+func TestFromTask(t *testing.T) {
+	now := time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC)
+	task := &models.Task{
+		ID:          "task-1",
+		Description: "Write report",
+		Date:        json.RawMessage(`"2024-01-15"`),
+		Start:       json.RawMessage(`"09:00"`),
+		DueDate:     json.RawMessage(`"2024-01-20"`),
+		CreatedAt:   json.RawMessage(`"2024-01-10T08:00:00Z"`),
+		Priority:    intPtr(4),
+		Label:       strPtr("label-1"),
+		ListID:      strPtr("list-1"),
+		Complete:    true,
+	}
+
+	tw := FromTask(task, testNames, time.UTC, now)
+	expected := Task{
+		UUID:        UUID("task-1"),
+		Description: "Write report",
+		Status:      StatusCompleted,
+		Entry:       "20240110T080000Z",
+		End:         "20240116T120000Z",
+		Due:         "20240120T000000Z",
+		Scheduled:   "20240115T090000Z",
+		Priority:    "H",
+		Project:     "Clients",
+		Tags:        []string{"Deep_Work"},
+	}
+	got, _ := json.Marshal(tw)
+	want, _ := json.Marshal(expected)
+	if !bytes.Equal(got, want) {
+		t.Errorf("unexpected task:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	task := &models.Task{
+		ID:          "task-1",
+		Description: "Write report",
+		Date:        json.RawMessage(`"2024-01-15"`),
+		Start:       json.RawMessage(`"14:30"`),
+		Priority:    intPtr(2),
+		Label:       strPtr("label-1"),
+		ListID:      strPtr("list-1"),
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []Task{FromTask(task, testNames, time.UTC, time.Now())}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	tasks, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	req, warnings := tasks[0].CreateRequest(testNames, time.UTC)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+	if req.Description != "Write report" || *req.Date != "2024-01-15" || *req.Start != "14:30" ||
+		*req.Priority != 2 || *req.Label != "label-1" || *req.ListID != "list-1" {
+		t.Errorf("round trip lost data: %+v", req)
+	}
+}
+
+func TestPriorityTables(t *testing.T) {
+	for p := 1; p <= 4; p++ {
+		letter, ok := PriorityFromEllie[p]
+		if !ok {
+			t.Fatalf("priority %d has no Taskwarrior mapping", p)
+		}
+		back := PriorityToEllie[letter]
+		if back != p && !(p == 4 && back == 3) {
+			t.Errorf("priority %d exported as %s imports as %d", p, letter, back)
+		}
+	}
+}
+
+func TestUUID(t *testing.T) {
+	a := UUID("task-1")
+	if a != UUID("task-1") {
+		t.Error("UUID should be deterministic")
+	}
+	if a == UUID("task-2") {
+		t.Error("different tasks should get different UUIDs")
+	}
+	if len(a) != 36 || a[14] != '5' {
+		t.Errorf("unexpected UUID %q", a)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, nil); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected an empty array, got %q", buf.String())
+	}
+}
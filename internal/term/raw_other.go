@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+// MakeRaw is not supported on this platform
+func MakeRaw(f *os.File) (func() error, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
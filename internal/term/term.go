@@ -0,0 +1,46 @@
+// Package term queries and controls the terminal
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is used when the terminal width cannot be determined
+const DefaultWidth = 80
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Size returns the width and height of the terminal attached to f
+func Size(f *os.File) (width, height int, ok bool) {
+	width, height, err := size(int(f.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// Width returns the width of the terminal attached to f, falling back to
+// $COLUMNS and then DefaultWidth
+func Width(f *os.File) int {
+	if width, _, ok := Size(f); ok {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return DefaultWidth
+}
+
+// ColorEnabled reports whether colored output should be written to f:
+// it must be a terminal and NO_COLOR must not be set
+func ColorEnabled(f *os.File) bool {
+	return os.Getenv("NO_COLOR") == "" && IsTerminal(f)
+}
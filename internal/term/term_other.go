@@ -0,0 +1,11 @@
+//go:build !unix
+
+package term
+
+import "errors"
+
+var errUnsupported = errors.New("terminal size is not supported on this platform")
+
+func size(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package term
+
+import "golang.org/x/sys/unix"
+
+func size(fd int) (int, int, error) {
+	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
@@ -0,0 +1,108 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Phase is the part of a focus session that is running
+type Phase string
+
+const (
+	PhaseWork  Phase = "work"
+	PhaseBreak Phase = "break"
+)
+
+// staleAfter is how long past its end a focus phase may be before it is
+// assumed that the process running it died
+const staleAfter = time.Minute
+
+// Focus is a pomodoro session in progress. It is kept in the timer state
+// so that status bars can display it from another process.
+type Focus struct {
+	TaskID      string    `json:"task_id"`
+	Description string    `json:"description"`
+	Phase       Phase     `json:"phase"`
+	Round       int       `json:"round"`
+	Rounds      int       `json:"rounds"`
+	EndsAt      time.Time `json:"ends_at"`
+}
+
+// Remaining returns the time left in the current phase
+func (f *Focus) Remaining(now time.Time) time.Duration {
+	if now.After(f.EndsAt) {
+		return 0
+	}
+	return f.EndsAt.Sub(now)
+}
+
+// Stale reports whether the session was abandoned without cleaning up
+func (f *Focus) Stale(now time.Time) bool {
+	return now.Sub(f.EndsAt) > staleAfter
+}
+
+// StatusLine returns a one-line summary, e.g. "work 2/4 12:34 Write report"
+func (f *Focus) StatusLine(now time.Time) string {
+	remaining := f.Remaining(now).Round(time.Second)
+	return fmt.Sprintf("%s %d/%d %02d:%02d %s", f.Phase, f.Round, f.Rounds,
+		int(remaining/time.Minute), int(remaining%time.Minute/time.Second), f.Description)
+}
+
+// BeginFocus starts a work phase: the task's timer starts and the focus
+// state is set. It fails if a timer or another focus session is running.
+func (st *State) BeginFocus(taskID, description string, round, rounds int, work time.Duration, now time.Time) error {
+	st.dropStaleFocus(now)
+	if st.Focus != nil && (st.Focus.TaskID != taskID || st.Focus.Phase != PhaseBreak) {
+		return fmt.Errorf("a focus session is already running for %q", st.Focus.Description)
+	}
+	if err := st.Start(taskID, description, now); err != nil {
+		return err
+	}
+	st.Focus = &Focus{
+		TaskID:      taskID,
+		Description: description,
+		Phase:       PhaseWork,
+		Round:       round,
+		Rounds:      rounds,
+		EndsAt:      now.Add(work),
+	}
+	return nil
+}
+
+// BeginBreak finishes the work phase, logging it, and starts a break
+func (st *State) BeginBreak(breakTime time.Duration, now time.Time) (*Entry, error) {
+	if st.Focus == nil || st.Focus.Phase != PhaseWork {
+		return nil, fmt.Errorf("no focus round running")
+	}
+	entry, err := st.Stop(now)
+	if err != nil {
+		return nil, err
+	}
+	entry.Source = "focus"
+	st.Focus.Phase = PhaseBreak
+	st.Focus.EndsAt = now.Add(breakTime)
+	return entry, nil
+}
+
+// EndFocus finishes the session. A work phase that is still running is
+// discarded, only completed rounds are logged.
+func (st *State) EndFocus() {
+	if st.Focus != nil && st.Focus.Phase == PhaseWork {
+		st.Active = nil
+	}
+	st.Focus = nil
+}
+
+// dropStaleFocus discards a session whose process died, together with
+// the work phase it left running, so that it does not block new timers
+// or get logged as hours of work
+func (st *State) dropStaleFocus(now time.Time) {
+	if st.Focus != nil && st.Focus.Stale(now) {
+		st.EndFocus()
+	}
+}
+
+// FocusRunning reports whether a live focus session owns the timer
+func (st *State) FocusRunning(now time.Time) bool {
+	return st.Focus != nil && !st.Focus.Stale(now)
+}
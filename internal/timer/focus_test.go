@@ -0,0 +1,103 @@
+package timer
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFocusRounds(t *testing.T) {
+	st := &State{}
+	work, brk := 25*time.Minute, 5*time.Minute
+
+	if err := st.BeginFocus("task-1", "Write report", 1, 2, work, t0); err != nil {
+		t.Fatalf("BeginFocus failed: %v", err)
+	}
+	if got := st.Focus.StatusLine(t0.Add(time.Minute)); got != "work 1/2 24:00 Write report" {
+		t.Errorf("unexpected status line %q", got)
+	}
+
+	entry, err := st.BeginBreak(brk, t0.Add(work))
+	if err != nil {
+		t.Fatalf("BeginBreak failed: %v", err)
+	}
+	if entry.Seconds != 1500 || entry.Source != "focus" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if st.Active != nil || st.Focus.Phase != PhaseBreak {
+		t.Errorf("expected a break without a running timer, got %+v %+v", st.Active, st.Focus)
+	}
+
+	next := t0.Add(work + brk)
+	if err := st.BeginFocus("task-1", "Write report", 2, 2, work, next); err != nil {
+		t.Fatalf("second round failed: %v", err)
+	}
+	st.EndFocus()
+	if st.Focus != nil || st.Active != nil || len(st.Log) != 1 {
+		t.Errorf("expected the session to be cleared, got %+v %+v %d", st.Focus, st.Active, len(st.Log))
+	}
+}
+
+func TestFocusGuards(t *testing.T) {
+	st := &State{}
+	st.Start("task-1", "Write report", t0)
+	if err := st.BeginFocus("task-2", "Other", 1, 4, time.Minute, t0); err == nil {
+		t.Error("expected focus to refuse while a timer runs")
+	}
+
+	st = &State{}
+	st.BeginFocus("task-1", "Write report", 1, 4, time.Minute, t0)
+	if !st.FocusRunning(t0) {
+		t.Error("expected focus to be running")
+	}
+	if err := st.BeginFocus("task-2", "Other", 1, 4, time.Minute, t0); err == nil {
+		t.Error("expected a second focus session to be refused")
+	}
+
+	// A session whose process died is taken over
+	later := t0.Add(time.Hour)
+	if st.FocusRunning(later) {
+		t.Error("expected the abandoned session to be stale")
+	}
+	if err := st.BeginFocus("task-2", "Other", 1, 4, time.Minute, later); err != nil {
+		t.Errorf("expected a stale session to be replaced, got %v", err)
+	}
+}
+
+func TestStaleFocusIsDiscarded(t *testing.T) {
+	later := t0.Add(3 * time.Hour)
+
+	st := &State{}
+	st.BeginFocus("task-1", "Write report", 1, 4, 25*time.Minute, t0)
+	if _, err := st.Stop(later); !errors.Is(err, ErrNoTimer) {
+		t.Errorf("expected stop to find no timer, got %v", err)
+	}
+	if st.Focus != nil || len(st.Log) != 0 {
+		t.Errorf("expected the abandoned session to be dropped unlogged, got %+v %d", st.Focus, len(st.Log))
+	}
+
+	st = &State{}
+	st.BeginFocus("task-1", "Write report", 1, 4, 25*time.Minute, t0)
+	if err := st.Start("task-2", "Other", later); err != nil {
+		t.Errorf("expected start to replace the abandoned session, got %v", err)
+	}
+}
+
+func TestStoreDropsStaleFocus(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "timer.json")}
+	start := time.Now().Add(-time.Hour)
+	if err := store.Update(func(st *State) error {
+		return st.BeginFocus("task-1", "Write report", 1, 4, 25*time.Minute, start)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Focus != nil || st.Active != nil {
+		t.Errorf("expected the abandoned session to be dropped, got %+v %+v", st.Focus, st.Active)
+	}
+}
@@ -0,0 +1,281 @@
+package timer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/config"
+)
+
+const stateFileName = "timer.json"
+
+// MaxLogEntries is the number of finished sessions kept in the log
+const MaxLogEntries = 500
+
+// lockTimeout is how long Update waits for another process to release
+// the state file, and how old a lock must be before it is considered stale
+const lockTimeout = 5 * time.Second
+
+// ErrNoTimer is returned when an operation needs a running timer
+var ErrNoTimer = errors.New("no timer running")
+
+// Session is the timer currently running or paused
+type Session struct {
+	TaskID      string        `json:"task_id"`
+	Description string        `json:"description"`
+	StartedAt   time.Time     `json:"started_at"`
+	ResumedAt   time.Time     `json:"resumed_at"`
+	Elapsed     time.Duration `json:"elapsed"`
+	Paused      bool          `json:"paused,omitempty"`
+}
+
+// ElapsedAt returns the time tracked by the session up to now
+func (s *Session) ElapsedAt(now time.Time) time.Duration {
+	if s.Paused || now.Before(s.ResumedAt) {
+		return s.Elapsed
+	}
+	return s.Elapsed + now.Sub(s.ResumedAt)
+}
+
+// Entry is a finished session
+type Entry struct {
+	ID          int       `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Description string    `json:"description"`
+	Source      string    `json:"source,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Seconds     int       `json:"seconds"`
+	// Saved is set once the seconds were added to the task's actual time
+	Saved bool `json:"saved"`
+}
+
+// State is the persisted timer state
+type State struct {
+	Active *Session `json:"active,omitempty"`
+	Focus  *Focus   `json:"focus,omitempty"`
+	Log    []Entry  `json:"log"`
+}
+
+// Start begins timing a task. Only one timer can run at a time.
+func (st *State) Start(taskID, description string, now time.Time) error {
+	st.dropStaleFocus(now)
+	if st.Active != nil {
+		return fmt.Errorf("a timer is already running for %q (%s), stop it first", st.Active.Description, st.Active.TaskID)
+	}
+	st.Active = &Session{
+		TaskID:      taskID,
+		Description: description,
+		StartedAt:   now,
+		ResumedAt:   now,
+	}
+	return nil
+}
+
+// Pause stops the clock without finishing the session
+func (st *State) Pause(now time.Time) error {
+	if st.Active == nil {
+		return ErrNoTimer
+	}
+	if st.Active.Paused {
+		return fmt.Errorf("timer is already paused")
+	}
+	st.Active.Elapsed = st.Active.ElapsedAt(now)
+	st.Active.Paused = true
+	return nil
+}
+
+// Resume restarts the clock of a paused session
+func (st *State) Resume(now time.Time) error {
+	if st.Active == nil {
+		return ErrNoTimer
+	}
+	if !st.Active.Paused {
+		return fmt.Errorf("timer is not paused")
+	}
+	st.Active.Paused = false
+	st.Active.ResumedAt = now
+	return nil
+}
+
+// Stop finishes the active session and logs it as not yet saved
+func (st *State) Stop(now time.Time) (*Entry, error) {
+	st.dropStaleFocus(now)
+	if st.Active == nil {
+		return nil, ErrNoTimer
+	}
+	session := st.Active
+	st.Active = nil
+	return st.Add(session.TaskID, session.Description, "timer", session.StartedAt, now, session.ElapsedAt(now)), nil
+}
+
+// Add logs time spent on a task outside of Start/Stop, e.g. a focus round
+func (st *State) Add(taskID, description, source string, start, end time.Time, elapsed time.Duration) *Entry {
+	id := 1
+	if len(st.Log) > 0 {
+		id = st.Log[len(st.Log)-1].ID + 1
+	}
+	st.Log = append(st.Log, Entry{
+		ID:          id,
+		TaskID:      taskID,
+		Description: description,
+		Source:      source,
+		Start:       start,
+		End:         end,
+		Seconds:     int(elapsed.Round(time.Second) / time.Second),
+	})
+	if len(st.Log) > MaxLogEntries {
+		st.Log = st.Log[len(st.Log)-MaxLogEntries:]
+	}
+	return &st.Log[len(st.Log)-1]
+}
+
+// Unsaved returns the IDs of logged entries whose time was not yet added
+// to their task
+func (st *State) Unsaved() []int {
+	var ids []int
+	for _, e := range st.Log {
+		if !e.Saved && e.Seconds > 0 {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// ClaimUnsaved marks the unsaved entries saved and returns copies of them.
+// Callers adding the time to tasks claim the entries inside Update before
+// calling the API, so that two processes cannot add the same entry twice,
+// and Release the ones they fail to save.
+func (st *State) ClaimUnsaved() []Entry {
+	var claimed []Entry
+	for _, id := range st.Unsaved() {
+		entry := st.Entry(id)
+		entry.Saved = true
+		claimed = append(claimed, *entry)
+	}
+	return claimed
+}
+
+// Release marks claimed entries unsaved again
+func (st *State) Release(entries []Entry) {
+	for _, e := range entries {
+		if entry := st.Entry(e.ID); entry != nil {
+			entry.Saved = false
+		}
+	}
+}
+
+// Entry returns the logged entry with the given ID
+func (st *State) Entry(id int) *Entry {
+	for i := range st.Log {
+		if st.Log[i].ID == id {
+			return &st.Log[i]
+		}
+	}
+	return nil
+}
+
+// Store persists the timer state in a file shared by all shells
+type Store struct {
+	Path string
+}
+
+// Open returns the store in the config directory
+func Open() (*Store, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Path: filepath.Join(configDir, stateFileName)}, nil
+}
+
+// Load reads the current state. A focus session abandoned by a crashed
+// process is dropped.
+func (s *Store) Load() (*State, error) {
+	st := &State{}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timer state: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("failed to parse timer state: %w", err)
+	}
+	st.dropStaleFocus(time.Now())
+	return st, nil
+}
+
+// Update loads the state, applies fn and saves the result while holding
+// a lock, so two shells cannot start timers at the same moment. Nothing is
+// saved when fn returns an error.
+func (s *Store) Update(fn func(*State) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	st, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if err := fn(st); err != nil {
+		return err
+	}
+	return s.save(st)
+}
+
+func (s *Store) save(st *State) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.Path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write timer state: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.Path); err != nil {
+		return fmt.Errorf("failed to write timer state: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	lockPath := s.Path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock timer state: %w", err)
+		}
+
+		// A lock left behind by a crashed process would block forever
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockTimeout {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timer state is locked by another ellie process (remove %s if none is running)", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,155 @@
+package timer
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+
+func TestStartStop(t *testing.T) {
+	st := &State{}
+	if err := st.Start("task-1", "Write report", t0); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := st.Start("task-2", "Other", t0); err == nil {
+		t.Error("expected starting a second timer to fail")
+	}
+
+	entry, err := st.Stop(t0.Add(25 * time.Minute))
+	if err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if entry.Seconds != 1500 || entry.TaskID != "task-1" || entry.Saved {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if st.Active != nil {
+		t.Error("expected no active timer after stop")
+	}
+	if _, err := st.Stop(t0); err != ErrNoTimer {
+		t.Errorf("expected ErrNoTimer, got %v", err)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	st := &State{}
+	st.Start("task-1", "Write report", t0)
+
+	if err := st.Resume(t0); err == nil {
+		t.Error("expected resuming a running timer to fail")
+	}
+	if err := st.Pause(t0.Add(10 * time.Minute)); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if err := st.Pause(t0.Add(11 * time.Minute)); err == nil {
+		t.Error("expected pausing twice to fail")
+	}
+	if got := st.Active.ElapsedAt(t0.Add(time.Hour)); got != 10*time.Minute {
+		t.Errorf("paused timer should not advance, got %v", got)
+	}
+	if err := st.Resume(t0.Add(time.Hour)); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	entry, _ := st.Stop(t0.Add(time.Hour + 5*time.Minute))
+	if entry.Seconds != 900 {
+		t.Errorf("expected 900 seconds, got %d", entry.Seconds)
+	}
+	if !entry.Start.Equal(t0) {
+		t.Errorf("entry should start when the timer started, got %v", entry.Start)
+	}
+}
+
+func TestUnsaved(t *testing.T) {
+	st := &State{}
+	st.Add("task-1", "One", "timer", t0, t0.Add(time.Minute), time.Minute)
+	st.Add("task-2", "Two", "timer", t0, t0, 0)
+	st.Add("task-3", "Three", "focus", t0, t0.Add(time.Minute), time.Minute)
+	st.Entry(1).Saved = true
+
+	ids := st.Unsaved()
+	if len(ids) != 1 || ids[0] != 3 {
+		t.Errorf("unexpected unsaved entries %v", ids)
+	}
+}
+
+func TestClaimUnsaved(t *testing.T) {
+	st := &State{}
+	st.Add("task-1", "One", "timer", t0, t0.Add(time.Minute), time.Minute)
+	st.Add("task-2", "Two", "timer", t0, t0.Add(time.Minute), time.Minute)
+
+	claimed := st.ClaimUnsaved()
+	if len(claimed) != 2 || !claimed[0].Saved {
+		t.Fatalf("unexpected claimed entries %+v", claimed)
+	}
+	if again := st.ClaimUnsaved(); len(again) != 0 {
+		t.Errorf("expected claimed entries not to be claimed again, got %+v", again)
+	}
+
+	st.Release(claimed[1:])
+	if ids := st.Unsaved(); len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("expected the released entry to be unsaved, got %v", ids)
+	}
+}
+
+func TestLogIsCapped(t *testing.T) {
+	st := &State{}
+	for i := 0; i < MaxLogEntries+10; i++ {
+		st.Add("task-1", "One", "timer", t0, t0, time.Second)
+	}
+	if len(st.Log) != MaxLogEntries {
+		t.Errorf("expected %d entries, got %d", MaxLogEntries, len(st.Log))
+	}
+	if st.Log[len(st.Log)-1].ID != MaxLogEntries+10 {
+		t.Errorf("IDs should keep increasing, got %d", st.Log[len(st.Log)-1].ID)
+	}
+}
+
+func TestStoreUpdate(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "timer.json")}
+
+	err := store.Update(func(st *State) error {
+		return st.Start("task-1", "Write report", t0)
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if st.Active == nil || st.Active.TaskID != "task-1" {
+		t.Errorf("expected the started timer to be persisted, got %+v", st.Active)
+	}
+}
+
+func TestStoreGuardsConcurrentStarts(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "timer.json")}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.Update(func(st *State) error {
+				return st.Start("task-1", "Write report", time.Now())
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	started := 0
+	for err := range errs {
+		if err == nil {
+			started++
+		}
+	}
+	if started != 1 {
+		t.Errorf("expected exactly one timer to start, got %d", started)
+	}
+}
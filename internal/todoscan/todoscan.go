@@ -0,0 +1,268 @@
+package todoscan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultTags are the comment markers picked up when none are given
+var DefaultTags = []string{"TODO", "FIXME", "HACK"}
+
+// MaxFileSize is the largest file that is scanned
+const MaxFileSize = 1 << 20
+
+// Syntax describes how a language writes comments
+type Syntax struct {
+	Line       []string
+	BlockStart string
+	BlockEnd   string
+}
+
+var (
+	cStyle    = Syntax{Line: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+	hashStyle = Syntax{Line: []string{"#"}}
+	dashStyle = Syntax{Line: []string{"--"}}
+	lispStyle = Syntax{Line: []string{";"}}
+	htmlStyle = Syntax{BlockStart: "<!--", BlockEnd: "-->"}
+)
+
+// Languages maps file extensions (and a few well-known file names) to
+// their comment syntax. Files not listed here are not scanned.
+var Languages = map[string]Syntax{
+	".go": cStyle, ".c": cStyle, ".h": cStyle, ".cc": cStyle, ".cpp": cStyle, ".hpp": cStyle,
+	".java": cStyle, ".kt": cStyle, ".scala": cStyle, ".cs": cStyle, ".swift": cStyle,
+	".js": cStyle, ".jsx": cStyle, ".ts": cStyle, ".tsx": cStyle, ".mjs": cStyle,
+	".rs": cStyle, ".dart": cStyle, ".zig": {Line: []string{"//"}},
+	".php":  {Line: []string{"//", "#"}, BlockStart: "/*", BlockEnd: "*/"},
+	".css":  {BlockStart: "/*", BlockEnd: "*/"},
+	".scss": cStyle, ".less": cStyle,
+	".py": hashStyle, ".rb": hashStyle, ".sh": hashStyle, ".bash": hashStyle, ".zsh": hashStyle,
+	".pl": hashStyle, ".r": hashStyle, ".nix": hashStyle, ".yaml": hashStyle, ".yml": hashStyle,
+	".toml": hashStyle, ".tf": hashStyle, ".ex": hashStyle, ".exs": hashStyle,
+	".sql": dashStyle, ".lua": dashStyle, ".hs": dashStyle, ".elm": dashStyle,
+	".el": lispStyle, ".lisp": lispStyle, ".clj": lispStyle, ".scm": lispStyle,
+	".erl": {Line: []string{"%"}}, ".tex": {Line: []string{"%"}},
+	".vim":  {Line: []string{"\""}},
+	".html": htmlStyle, ".xml": htmlStyle, ".vue": {Line: []string{"//"}, BlockStart: "<!--", BlockEnd: "-->"},
+	".md":      htmlStyle,
+	"Makefile": hashStyle, "Dockerfile": hashStyle, "Justfile": hashStyle,
+}
+
+// skipDirs are directories that never contain code worth scanning
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// Comment is a marker comment found in a source file
+type Comment struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Tag     string `json:"tag"`
+	Text    string `json:"text"`
+	Context string `json:"context"`
+	Key     string `json:"key"`
+}
+
+// Location returns the comment's file:line
+func (c *Comment) Location() string {
+	return fmt.Sprintf("%s:%d", c.Path, c.Line)
+}
+
+// Description returns the task description for the comment
+func (c *Comment) Description() string {
+	text := c.Text
+	if text == "" {
+		text = "(no description)"
+	}
+	return fmt.Sprintf("%s: %s (%s)", c.Tag, text, c.Location())
+}
+
+// Scanner finds marker comments in source trees
+type Scanner struct {
+	pattern *regexp.Regexp
+}
+
+// New returns a scanner for the given comment tags, e.g. TODO and FIXME
+func New(tags []string) (*Scanner, error) {
+	if len(tags) == 0 {
+		tags = DefaultTags
+	}
+	quoted := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("comment tags cannot be empty")
+		}
+		quoted = append(quoted, regexp.QuoteMeta(tag))
+	}
+	pattern, err := regexp.Compile(`(?:^|[^\w])(` + strings.Join(quoted, "|") + `)(?:\([^)]*\))?(?:[:\-\s]\s*(.*))?$`)
+	if err != nil {
+		return nil, err
+	}
+	return &Scanner{pattern: pattern}, nil
+}
+
+// Walk scans every recognised source file under root. Hidden directories
+// and dependency directories such as node_modules and vendor are skipped.
+func (s *Scanner) Walk(root string) ([]Comment, error) {
+	var comments []Comment
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		syntax, ok := SyntaxFor(path)
+		if !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > MaxFileSize {
+			return nil
+		}
+
+		found, err := s.scanFile(path, syntax)
+		if err != nil {
+			return err
+		}
+		comments = append(comments, found...)
+		return nil
+	})
+	return comments, err
+}
+
+// SyntaxFor returns the comment syntax of a file, by name or extension
+func SyntaxFor(path string) (Syntax, bool) {
+	base := filepath.Base(path)
+	if syntax, ok := Languages[base]; ok {
+		return syntax, true
+	}
+	syntax, ok := Languages[strings.ToLower(filepath.Ext(base))]
+	return syntax, ok
+}
+
+func (s *Scanner) scanFile(path string, syntax Syntax) ([]Comment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, nil
+	}
+	return s.Scan(bytes.NewReader(data), filepath.ToSlash(path), syntax)
+}
+
+// Scan extracts marker comments from a single file's contents. Each
+// comment's Key identifies it by file, tag and text, so that it survives
+// lines being inserted above it; repeated identical comments in a file are
+// numbered.
+func (s *Scanner) Scan(r io.Reader, path string, syntax Syntax) ([]Comment, error) {
+	var comments []Comment
+	seen := make(map[string]int)
+	inBlock := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxFileSize)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		var texts []string
+		texts, inBlock = commentText(line, syntax, inBlock)
+		for _, text := range texts {
+			m := s.pattern.FindStringSubmatch(text)
+			if m == nil {
+				continue
+			}
+			c := Comment{
+				Path:    path,
+				Line:    lineNo,
+				Tag:     m[1],
+				Text:    cleanText(m[2], syntax),
+				Context: strings.TrimSpace(line),
+			}
+			c.Key = path + "|" + c.Tag + "|" + c.Text
+			seen[c.Key]++
+			if n := seen[c.Key]; n > 1 {
+				c.Key += fmt.Sprintf("|%d", n)
+			}
+			comments = append(comments, c)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+	return comments, nil
+}
+
+// commentText returns the comment portions of a line and whether a block
+// comment is still open at its end. String literals are not tracked, so a
+// comment marker inside a string counts as a comment.
+func commentText(line string, syntax Syntax, inBlock bool) ([]string, bool) {
+	var texts []string
+	rest := line
+	for rest != "" {
+		if inBlock {
+			end := strings.Index(rest, syntax.BlockEnd)
+			if end < 0 {
+				texts = append(texts, rest)
+				return texts, true
+			}
+			texts = append(texts, rest[:end])
+			rest = rest[end+len(syntax.BlockEnd):]
+			inBlock = false
+			continue
+		}
+
+		lineAt, lineLen := -1, 0
+		for _, marker := range syntax.Line {
+			if i := strings.Index(rest, marker); i >= 0 && (lineAt < 0 || i < lineAt) {
+				lineAt, lineLen = i, len(marker)
+			}
+		}
+		blockAt := -1
+		if syntax.BlockStart != "" {
+			blockAt = strings.Index(rest, syntax.BlockStart)
+		}
+
+		switch {
+		case lineAt >= 0 && (blockAt < 0 || lineAt < blockAt):
+			texts = append(texts, rest[lineAt+lineLen:])
+			return texts, false
+		case blockAt >= 0:
+			rest = rest[blockAt+len(syntax.BlockStart):]
+			inBlock = true
+		default:
+			return texts, false
+		}
+	}
+	return texts, inBlock
+}
+
+func cleanText(text string, syntax Syntax) string {
+	text = strings.TrimSpace(text)
+	if syntax.BlockEnd != "" {
+		text = strings.TrimSpace(strings.TrimSuffix(text, syntax.BlockEnd))
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,156 @@
+package todoscan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func scan(t *testing.T, path, src string) []Comment {
+	t.Helper()
+	s, err := New(nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	syntax, ok := SyntaxFor(path)
+	if !ok {
+		t.Fatalf("no syntax for %s", path)
+	}
+	comments, err := s.Scan(strings.NewReader(src), path, syntax)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	return comments
+}
+
+func TestScanGo(t *testing.T) {
+	src := `package main
+
+// TODO: handle retries
+func main() {
+	x := 1 // FIXME(bob): off by one
+	/* HACK work around
+	   the TODO in the vendor lib */
+	s := "TODOs are fun"
+	_ = s
+	// Not a todo: TODOLIST
+}
+`
+	comments := scan(t, "main.go", src)
+	if len(comments) != 4 {
+		t.Fatalf("expected 4 comments, got %d: %+v", len(comments), comments)
+	}
+
+	expected := []struct {
+		line int
+		tag  string
+		text string
+	}{
+		{3, "TODO", "handle retries"},
+		{5, "FIXME", "off by one"},
+		{6, "HACK", "work around"},
+		{7, "TODO", "in the vendor lib"},
+	}
+	for i, want := range expected {
+		c := comments[i]
+		if c.Line != want.line || c.Tag != want.tag || c.Text != want.text {
+			t.Errorf("comment %d: got %d %s %q, want %d %s %q", i, c.Line, c.Tag, c.Text, want.line, want.tag, want.text)
+		}
+	}
+	if comments[0].Description() != "TODO: handle retries (main.go:3)" {
+		t.Errorf("unexpected description %q", comments[0].Description())
+	}
+}
+
+func TestScanOtherLanguages(t *testing.T) {
+	tests := []struct {
+		path string
+		src  string
+		text string
+	}{
+		{"script.py", "x = 1  # TODO: use a config file\n", "use a config file"},
+		{"query.sql", "SELECT 1; -- FIXME slow\n", "slow"},
+		{"init.el", ";; TODO bind keys\n", "bind keys"},
+		{"index.html", "<div><!-- TODO: alt text --></div>\n", "alt text"},
+		{"Makefile", "build: # HACK: skip tests\n", "skip tests"},
+	}
+	for _, tt := range tests {
+		comments := scan(t, tt.path, tt.src)
+		if len(comments) != 1 || comments[0].Text != tt.text {
+			t.Errorf("%s: unexpected comments %+v", tt.path, comments)
+		}
+	}
+}
+
+func TestScanCodeIsNotAComment(t *testing.T) {
+	comments := scan(t, "main.go", "TODO := 1\n")
+	if len(comments) != 0 {
+		t.Errorf("expected no comments outside comment syntax, got %+v", comments)
+	}
+}
+
+func TestKeysSurviveLineShifts(t *testing.T) {
+	before := scan(t, "a.go", "// TODO: one\n// TODO: one\n")
+	after := scan(t, "a.go", "package a\n\n// TODO: one\n// TODO: one\n")
+	if before[0].Key == before[1].Key {
+		t.Error("identical comments should get distinct keys")
+	}
+	for i := range before {
+		if before[i].Key != after[i].Key {
+			t.Errorf("key changed after inserting lines: %q vs %q", before[i].Key, after[i].Key)
+		}
+	}
+}
+
+func TestCustomTags(t *testing.T) {
+	s, err := New([]string{"XXX"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	comments, err := s.Scan(strings.NewReader("// XXX: fix\n// TODO: ignored\n"), "a.go", Languages[".go"])
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Tag != "XXX" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":               "// TODO: root\n",
+		"pkg/util.py":           "# FIXME: nested\n",
+		"node_modules/lib/x.js": "// TODO: dependency\n",
+		".git/hooks/pre-commit": "# TODO: hidden\n",
+		"notes.txt":             "TODO: unknown extension\n",
+		"image.go":              "// TODO: binary\x00",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := New(nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	comments, err := s.Walk(root)
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+
+	var texts []string
+	for _, c := range comments {
+		texts = append(texts, c.Text)
+	}
+	if strings.Join(texts, ",") != "root,nested" {
+		t.Errorf("unexpected comments %v", texts)
+	}
+}
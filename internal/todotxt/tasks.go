@@ -0,0 +1,116 @@
+package todotxt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/resolve"
+)
+
+const (
+	// dueTag holds the task's due date
+	dueTag = "due"
+	// thresholdTag holds the date the task is scheduled for
+	thresholdTag = "t"
+)
+
+// FromTask converts a task into a todo.txt item. Lists become +projects,
+// labels become @contexts and the scheduled date is written as t:.
+func FromTask(task *models.Task, names models.Names) Item {
+	item := Item{
+		Complete: task.Complete,
+		Text:     strings.Join(strings.Fields(task.Description), " "),
+		Tags:     make(map[string]string),
+	}
+
+	if task.Priority != nil {
+		item.Priority = models.PriorityLetter(*task.Priority)
+	}
+	if task.ListID != nil {
+		if title, ok := names.Lists[*task.ListID]; ok {
+			item.Projects = append(item.Projects, tokenize(title))
+		}
+	}
+	if task.Label != nil {
+		if name, ok := names.Labels[*task.Label]; ok {
+			item.Contexts = append(item.Contexts, tokenize(name))
+		}
+	}
+	if date := task.GetDateString(); date != "" {
+		item.Tags[thresholdTag] = date
+	}
+	if due, ok := dateutil.ParseDay(task.GetDueDateString(), time.Local); ok {
+		item.Tags[dueTag] = dateutil.Format(due)
+	}
+	if created, ok := dateutil.ParseDay(task.GetCreatedAtString(), time.Local); ok {
+		item.CreationDate = dateutil.Format(created)
+	}
+	if task.Complete {
+		if completed, ok := dateutil.ParseDay(task.GetCompletedAtString(), time.Local); ok {
+			item.CompletionDate = dateutil.Format(completed)
+		}
+	}
+
+	return item
+}
+
+// ToRequest converts an item into a create request. Projects and contexts
+// are matched against list and label names as described in package
+// resolve; the first match wins and the rest are reported as warnings.
+func ToRequest(item Item, names models.Names) (*models.CreateTaskRequest, []string) {
+	req := &models.CreateTaskRequest{Description: item.Text}
+	var warnings []string
+
+	if p := models.PriorityFromLetter(item.Priority); p > 0 {
+		req.Priority = &p
+	}
+	if date, ok := item.Tags[thresholdTag]; ok && datePattern.MatchString(date) {
+		req.Date = &date
+	}
+	if due, ok := item.Tags[dueTag]; ok {
+		if datePattern.MatchString(due) {
+			req.DueDate = &due
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid due date %q", due))
+		}
+	}
+
+	for _, project := range item.Projects {
+		id, err := resolve.ListID(names, project)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("project +%s ignored: %v", project, err))
+		case req.ListID == nil:
+			req.ListID = &id
+		default:
+			warnings = append(warnings, fmt.Sprintf("project +%s ignored, tasks belong to a single list", project))
+		}
+	}
+	for _, context := range item.Contexts {
+		id, err := resolve.LabelID(names, context)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("context @%s ignored: %v", context, err))
+		case req.Label == nil:
+			req.Label = &id
+		default:
+			warnings = append(warnings, fmt.Sprintf("context @%s ignored, tasks have a single label", context))
+		}
+	}
+
+	return req, warnings
+}
+
+// Key identifies an item across imports. Completion and priority are
+// left out so that ticking off a line does not make it look new.
+func (i Item) Key() string {
+	return i.Text + "|" + i.Tags[thresholdTag]
+}
+
+// tokenize turns a name into a single todo.txt word
+func tokenize(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
@@ -0,0 +1,163 @@
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	datePattern   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	tagKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+)
+
+// Item is a single todo.txt line. Projects, contexts and key:value tags
+// are split out of the text and written back after it.
+type Item struct {
+	Complete       bool
+	Priority       string
+	CompletionDate string
+	CreationDate   string
+	Text           string
+	Projects       []string
+	Contexts       []string
+	Tags           map[string]string
+}
+
+// Parse reads one line. It returns false for blank lines.
+func Parse(line string) (Item, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Item{}, false
+	}
+
+	item := Item{Tags: make(map[string]string)}
+
+	if fields[0] == "x" {
+		item.Complete = true
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && isPriority(fields[0]) {
+		item.Priority = fields[0][1:2]
+		fields = fields[1:]
+	}
+	if item.Complete && len(fields) > 0 && datePattern.MatchString(fields[0]) {
+		item.CompletionDate = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && datePattern.MatchString(fields[0]) {
+		item.CreationDate = fields[0]
+		fields = fields[1:]
+	}
+
+	var words []string
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '+':
+			item.Projects = append(item.Projects, field[1:])
+		case len(field) > 1 && field[0] == '@':
+			item.Contexts = append(item.Contexts, field[1:])
+		case isTag(field):
+			key, value, _ := strings.Cut(field, ":")
+			item.Tags[key] = value
+		default:
+			words = append(words, field)
+		}
+	}
+	item.Text = strings.Join(words, " ")
+
+	// Completed tasks conventionally keep their priority as a pri: tag
+	if pri, ok := item.Tags["pri"]; ok && item.Priority == "" && len(pri) == 1 {
+		item.Priority = strings.ToUpper(pri)
+		delete(item.Tags, "pri")
+	}
+
+	return item, true
+}
+
+// String formats the item as a todo.txt line
+func (i Item) String() string {
+	var parts []string
+
+	if i.Complete {
+		parts = append(parts, "x")
+		if i.CompletionDate != "" {
+			parts = append(parts, i.CompletionDate)
+		}
+	} else if i.Priority != "" {
+		parts = append(parts, "("+i.Priority+")")
+	}
+	if i.CreationDate != "" {
+		parts = append(parts, i.CreationDate)
+	}
+	if i.Text != "" {
+		parts = append(parts, i.Text)
+	}
+	for _, project := range i.Projects {
+		parts = append(parts, "+"+project)
+	}
+	for _, context := range i.Contexts {
+		parts = append(parts, "@"+context)
+	}
+
+	keys := make([]string, 0, len(i.Tags))
+	for key := range i.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+":"+i.Tags[key])
+	}
+	if i.Complete && i.Priority != "" {
+		parts = append(parts, "pri:"+i.Priority)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Read parses every non-blank line of r. Line numbers start at 1.
+func Read(r io.Reader) ([]Item, []int, error) {
+	var items []Item
+	var lines []int
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if item, ok := Parse(scanner.Text()); ok {
+			items = append(items, item)
+			lines = append(lines, lineNo)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read todo.txt: %w", err)
+	}
+	return items, lines, nil
+}
+
+// Write formats items one per line
+func Write(w io.Writer, items []Item) error {
+	bw := bufio.NewWriter(w)
+	for _, item := range items {
+		bw.WriteString(item.String())
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+func isPriority(s string) bool {
+	return len(s) == 3 && s[0] == '(' && s[2] == ')' && s[1] >= 'A' && s[1] <= 'Z'
+}
+
+// isTag reports whether a word is a key:value tag. URLs and times like
+// 10:30 are not tags.
+func isTag(s string) bool {
+	key, value, ok := strings.Cut(s, ":")
+	if !ok || value == "" || strings.HasPrefix(value, "//") {
+		return false
+	}
+	return tagKeyPattern.MatchString(key)
+}
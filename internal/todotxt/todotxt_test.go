@@ -0,0 +1,147 @@
+package todotxt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestParse(t *testing.T) {
+	item, ok := Parse("(A) 2024-01-10 Call Mom about 10:30 dinner +Family @phone due:2024-01-12 https://example.com")
+	if !ok {
+		t.Fatal("expected item")
+	}
+
+	if item.Priority != "A" {
+		t.Errorf("expected priority A, got %q", item.Priority)
+	}
+	if item.CreationDate != "2024-01-10" {
+		t.Errorf("expected creation date, got %q", item.CreationDate)
+	}
+	if item.Text != "Call Mom about 10:30 dinner https://example.com" {
+		t.Errorf("unexpected text: %q", item.Text)
+	}
+	if len(item.Projects) != 1 || item.Projects[0] != "Family" {
+		t.Errorf("unexpected projects: %v", item.Projects)
+	}
+	if len(item.Contexts) != 1 || item.Contexts[0] != "phone" {
+		t.Errorf("unexpected contexts: %v", item.Contexts)
+	}
+	if item.Tags["due"] != "2024-01-12" {
+		t.Errorf("expected due tag, got %v", item.Tags)
+	}
+}
+
+func TestParse_Completed(t *testing.T) {
+	item, ok := Parse("x 2024-01-11 2024-01-10 Pay rent pri:B")
+	if !ok {
+		t.Fatal("expected item")
+	}
+	if !item.Complete || item.CompletionDate != "2024-01-11" || item.CreationDate != "2024-01-10" {
+		t.Errorf("unexpected completion fields: %+v", item)
+	}
+	if item.Priority != "B" {
+		t.Errorf("expected priority B from pri tag, got %q", item.Priority)
+	}
+
+	if _, ok := Parse("   "); ok {
+		t.Error("expected blank line to be skipped")
+	}
+}
+
+func TestRoundTrip_Lines(t *testing.T) {
+	lines := []string{
+		"(B) 2024-01-10 Write report +Work @office due:2024-01-15 t:2024-01-14",
+		"x 2024-01-11 2024-01-10 Pay rent +Home pri:A",
+		"Plain task",
+	}
+
+	var items []Item
+	for _, line := range lines {
+		item, _ := Parse(line)
+		items = append(items, item)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for i := range lines {
+		if got[i] != lines[i] {
+			t.Errorf("line %d: expected %q, got %q", i, lines[i], got[i])
+		}
+	}
+}
+
+func TestRoundTrip_Tasks(t *testing.T) {
+	names := models.Names{
+		Lists:  map[string]string{"list-1": "Side Project"},
+		Labels: map[string]string{"label-1": "Deep Work"},
+	}
+
+	task := &models.Task{
+		ID:          "task-1",
+		Description: "Ship the beta",
+		Date:        json.RawMessage(`"2024-01-14"`),
+		DueDate:     json.RawMessage(`"2024-01-15"`),
+		Priority:    intPtr(4),
+		ListID:      strPtr("list-1"),
+		Label:       strPtr("label-1"),
+	}
+
+	line := FromTask(task, names).String()
+	if line != "(A) Ship the beta +Side-Project @Deep-Work due:2024-01-15 t:2024-01-14" {
+		t.Errorf("unexpected line: %q", line)
+	}
+
+	item, _ := Parse(line)
+	req, warnings := ToRequest(item, names)
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if req.Description != "Ship the beta" {
+		t.Errorf("unexpected description: %q", req.Description)
+	}
+	if req.Priority == nil || *req.Priority != 4 {
+		t.Errorf("expected priority 4, got %v", req.Priority)
+	}
+	if req.ListID == nil || *req.ListID != "list-1" {
+		t.Errorf("expected list-1, got %v", req.ListID)
+	}
+	if req.Label == nil || *req.Label != "label-1" {
+		t.Errorf("expected label-1, got %v", req.Label)
+	}
+	if req.Date == nil || *req.Date != "2024-01-14" {
+		t.Errorf("expected date 2024-01-14, got %v", req.Date)
+	}
+	if req.DueDate == nil || *req.DueDate != "2024-01-15" {
+		t.Errorf("expected due date 2024-01-15, got %v", req.DueDate)
+	}
+}
+
+func TestPriorityMapping(t *testing.T) {
+	expected := map[string]int{"A": 4, "B": 3, "C": 2, "D": 1, "E": 1}
+	for letter, priority := range expected {
+		if got := models.PriorityFromLetter(letter); got != priority {
+			t.Errorf("(%s): expected %d, got %d", letter, priority, got)
+		}
+	}
+	for p := 1; p <= 4; p++ {
+		if got := models.PriorityFromLetter(models.PriorityLetter(p)); got != p {
+			t.Errorf("priority %d did not round-trip, got %d", p, got)
+		}
+	}
+}
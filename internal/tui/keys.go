@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"unicode/utf8"
+)
+
+// csiKeys names the final bytes of cursor and editing key sequences
+var csiKeys = map[string]string{
+	"A": "up", "B": "down", "C": "right", "D": "left",
+	"H": "home", "F": "end", "Z": "shift-tab",
+	"1~": "home", "4~": "end", "3~": "delete", "5~": "pgup", "6~": "pgdown",
+}
+
+// parseKeys splits what the terminal sent in one read into key names.
+// Printable keys are named by their character, others by names such as
+// "enter", "esc", "up" or "ctrl-c". A lone escape byte is taken as the
+// Escape key, since sequences arrive in a single read.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == 0x1b && i+1 < len(b) && (b[i+1] == '[' || b[i+1] == 'O'):
+			j := i + 2
+			for j < len(b) && (b[j] < 0x40 || b[j] > 0x7e) {
+				j++
+			}
+			if j == len(b) {
+				return append(keys, "esc")
+			}
+			if name, ok := csiKeys[string(b[i+2:j+1])]; ok {
+				keys = append(keys, name)
+			}
+			i = j + 1
+		case c == 0x1b:
+			keys = append(keys, "esc")
+			i++
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+			i++
+		case c == '\t':
+			keys = append(keys, "tab")
+			i++
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, "backspace")
+			i++
+		case c < 0x20:
+			keys = append(keys, "ctrl-"+string(rune('a'+c-1)))
+			i++
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+			i += size
+		}
+	}
+	return keys
+}
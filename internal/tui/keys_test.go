@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"jk", []string{"j", "k"}},
+		{"\x1b[A\x1b[B\x1bOC", []string{"up", "down", "right"}},
+		{"\x1b", []string{"esc"}},
+		{"\x1b[Z", []string{"shift-tab"}},
+		{"\x1b[6~", []string{"pgdown"}},
+		{"\r\t\x7f\x03", []string{"enter", "tab", "backspace", "ctrl-c"}},
+		{"ü", []string{"ü"}},
+		{"\x1b[1;5A", nil},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,716 @@
+// Package tui is a full-screen terminal interface for the agenda, the
+// braindump and lists. Changes are shown at once and sent to the API in
+// the background; a failed request puts the task back.
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+// Client is the part of api.Client the interface uses
+type Client interface {
+	GetTasksForDate(date string) ([]models.Task, error)
+	GetBraindump() ([]models.Task, error)
+	GetLists() ([]models.List, error)
+	GetTasksByList(listID string) ([]models.Task, error)
+	UpdateTask(taskID string, req *models.UpdateTaskRequest) (*models.Task, error)
+	MarkTaskComplete(taskID string) (*models.Task, error)
+}
+
+// Pane identifies one of the task columns
+type Pane int
+
+const (
+	PaneAgenda Pane = iota
+	PaneBraindump
+	PaneLists
+)
+
+const paneCount = 3
+
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeSearch
+	modeEdit
+	modeReschedule
+)
+
+// Msg is something for Update to handle: a key press or the result of a
+// Cmd
+type Msg interface{}
+
+// Cmd is work done in the background, typically an API call. Its result
+// is passed back to Update.
+type Cmd func() Msg
+
+// KeyMsg is a key press, named as by parseKeys
+type KeyMsg string
+
+type loadedMsg struct {
+	date      string
+	agenda    []models.Task
+	braindump []models.Task
+	lists     []models.List
+	listID    string
+	listTasks []models.Task
+	err       error
+}
+
+type agendaMsg struct {
+	date  string
+	tasks []models.Task
+	err   error
+}
+
+type listMsg struct {
+	listID string
+	tasks  []models.Task
+	err    error
+}
+
+type savedMsg struct {
+	action string
+	before models.Task
+	req    *models.UpdateTaskRequest
+	err    error
+}
+
+// Options configures a Model
+type Options struct {
+	// Date is the first day shown in the agenda, default today
+	Date     string
+	Location *time.Location
+	// Now defaults to time.Now
+	Now func() time.Time
+	// OnChange is called with a task's previous state after a change
+	// has been saved
+	OnChange func(action string, before models.Task)
+}
+
+type pane struct {
+	tasks  []models.Task
+	cursor int
+	offset int
+}
+
+// Model is the state of the interface
+type Model struct {
+	client Client
+	opts   Options
+
+	date  string
+	lists []models.List
+	list  int
+	panes [paneCount]pane
+	focus Pane
+
+	mode   mode
+	query  string
+	input  []rune
+	target models.Task
+	status string
+
+	// pending holds the local state of tasks with requests in flight, so
+	// that reloads do not undo them. saved is their state as far as the
+	// API has confirmed it, and failed marks those a request failed for.
+	pending  map[string]models.Task
+	saved    map[string]models.Task
+	failed   map[string]bool
+	inflight map[string]int
+
+	quit bool
+}
+
+// New returns a Model showing opts.Date
+func New(client Client, opts Options) *Model {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	m := &Model{
+		client:   client,
+		opts:     opts,
+		date:     opts.Date,
+		pending:  make(map[string]models.Task),
+		saved:    make(map[string]models.Task),
+		failed:   make(map[string]bool),
+		inflight: make(map[string]int),
+	}
+	if m.date == "" {
+		m.date = m.today()
+	}
+	return m
+}
+
+// Init returns the Cmd loading all panes
+func (m *Model) Init() Cmd {
+	m.status = "Loading…"
+	return m.loadAll()
+}
+
+// Done reports whether the user has quit
+func (m *Model) Done() bool {
+	return m.quit
+}
+
+// Saving reports whether changes are still being sent to the API
+func (m *Model) Saving() bool {
+	return len(m.inflight) > 0
+}
+
+// Update handles a message and returns the next Cmd to run, if any
+func (m *Model) Update(msg Msg) Cmd {
+	switch msg := msg.(type) {
+	case KeyMsg:
+		if m.mode != modeNormal {
+			return m.promptKey(string(msg))
+		}
+		return m.key(string(msg))
+
+	case batchMsg:
+		var cmds []Cmd
+		for _, sub := range msg {
+			cmds = append(cmds, m.Update(sub))
+		}
+		return batch(cmds...)
+
+	case loadedMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return nil
+		}
+		m.status = ""
+		m.lists = msg.lists
+		m.list = 0
+		for i, l := range m.lists {
+			if l.ID == msg.listID {
+				m.list = i
+			}
+		}
+		m.panes[PaneBraindump].tasks = msg.braindump
+		m.panes[PaneLists].tasks = msg.listTasks
+		if msg.date == m.date {
+			m.setAgenda(msg.agenda)
+		}
+		m.reapply()
+
+	case agendaMsg:
+		if msg.date != m.date {
+			return nil
+		}
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return nil
+		}
+		m.setAgenda(msg.tasks)
+		m.reapply()
+
+	case listMsg:
+		if msg.listID != m.listID() {
+			return nil
+		}
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return nil
+		}
+		m.panes[PaneLists].tasks = msg.tasks
+		m.reapply()
+
+	case savedMsg:
+		id := msg.before.ID
+		if msg.err != nil {
+			m.failed[id] = true
+			m.status = fmt.Sprintf("Failed to %s %q: %v", msg.action, msg.before.Description, msg.err)
+		} else {
+			// msg.before may include earlier changes that failed, so
+			// the undo journal gets the confirmed state instead
+			before := m.saved[id]
+			m.saved[id] = applyUpdate(before, msg.req)
+			if m.opts.OnChange != nil {
+				m.opts.OnChange(msg.action, before)
+			}
+		}
+		if m.inflight[id]--; m.inflight[id] <= 0 {
+			// Later changes shown so far include the failed ones, so
+			// the task is rebuilt from what was saved once none are
+			// left in flight
+			if m.failed[id] {
+				m.place(m.saved[id])
+			}
+			delete(m.inflight, id)
+			delete(m.pending, id)
+			delete(m.saved, id)
+			delete(m.failed, id)
+		}
+	}
+
+	m.clamp()
+	return nil
+}
+
+func (m *Model) key(k string) Cmd {
+	m.status = ""
+	p := &m.panes[m.focus]
+
+	switch k {
+	case "q", "ctrl-c":
+		m.quit = true
+	case "tab", "l", "right":
+		m.focus = (m.focus + 1) % paneCount
+	case "shift-tab", "h", "left":
+		m.focus = (m.focus + paneCount - 1) % paneCount
+	case "j", "down":
+		p.cursor++
+	case "k", "up":
+		p.cursor--
+	case "g", "home":
+		p.cursor = 0
+	case "G", "end":
+		p.cursor = len(m.visible(m.focus)) - 1
+	case "pgdown":
+		p.cursor += 10
+	case "pgup":
+		p.cursor -= 10
+	case "<", ",":
+		return m.showDate(m.shift(m.date, -1))
+	case ">", ".":
+		return m.showDate(m.shift(m.date, 1))
+	case "t":
+		return m.showDate(m.today())
+	case "[":
+		return m.showList(-1)
+	case "]":
+		return m.showList(1)
+	case "R", "ctrl-r":
+		m.status = "Loading…"
+		return m.loadAll()
+	case "/":
+		m.mode = modeSearch
+		m.input = []rune(m.query)
+	case "esc":
+		m.query = ""
+	default:
+		return m.taskKey(k)
+	}
+
+	m.clamp()
+	return nil
+}
+
+// taskKey handles the keys acting on the selected task
+func (m *Model) taskKey(k string) Cmd {
+	task, ok := m.selected()
+	if !ok {
+		return nil
+	}
+
+	switch k {
+	case "x", " ":
+		done := !task.Complete
+		if done {
+			m.status = "Completed " + task.Description
+			return m.update("complete", task, &models.UpdateTaskRequest{Complete: &done})
+		}
+		m.status = "Reopened " + task.Description
+		return m.update("reopen", task, &models.UpdateTaskRequest{Complete: &done})
+
+	case "e", "enter":
+		m.mode = modeEdit
+		m.target = task
+		m.input = []rune(task.Description)
+
+	case "r":
+		m.mode = modeReschedule
+		m.target = task
+		m.input = []rune(task.GetDateString())
+
+	case "H", "L":
+		date := task.GetDateString()
+		if date == "" {
+			m.status = "Task has no date, press m to move it to " + m.date
+			return nil
+		}
+		delta := 1
+		if k == "H" {
+			delta = -1
+		}
+		to := m.shift(date, delta)
+		cmd := m.moveTo(task, to, false)
+		// Follow the task like dragging it across days
+		load := m.showDate(to)
+		m.focus = PaneAgenda
+		m.selectID(PaneAgenda, task.ID)
+		return batch(cmd, load)
+
+	case "m":
+		return m.moveTo(task, m.date, false)
+
+	case "b":
+		return m.moveTo(task, "", true)
+	}
+	return nil
+}
+
+// promptKey edits the search, description or date being typed
+func (m *Model) promptKey(k string) Cmd {
+	switch k {
+	case "esc", "ctrl-c":
+		if m.mode == modeSearch {
+			m.query = ""
+		}
+		m.mode = modeNormal
+		m.input = nil
+	case "enter":
+		value := strings.TrimSpace(string(m.input))
+		md := m.mode
+		m.mode = modeNormal
+		m.input = nil
+		switch md {
+		case modeEdit:
+			return m.submitEdit(value)
+		case modeReschedule:
+			return m.submitReschedule(value)
+		}
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case "ctrl-u":
+		m.input = nil
+	default:
+		if r := []rune(k); len(r) == 1 && r[0] >= ' ' {
+			m.input = append(m.input, r[0])
+		}
+	}
+
+	if m.mode == modeSearch {
+		m.query = string(m.input)
+	}
+	m.clamp()
+	return nil
+}
+
+func (m *Model) submitEdit(desc string) Cmd {
+	if desc == "" || desc == m.target.Description {
+		return nil
+	}
+	m.status = "Renamed to " + desc
+	return m.update("edit", m.target, &models.UpdateTaskRequest{Description: &desc})
+}
+
+// submitReschedule parses "DATE [HH:MM]"; an empty value moves the task
+// to the braindump
+func (m *Model) submitReschedule(value string) Cmd {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return m.moveTo(m.target, "", true)
+	}
+
+	day, err := dateutil.Parse(fields[0], m.now())
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	date := dateutil.Format(day)
+	req := &models.UpdateTaskRequest{Date: &date}
+	if len(fields) > 1 {
+		start, err := planner.ParseClock(fields[1])
+		if err != nil {
+			m.status = err.Error()
+			return nil
+		}
+		s := start.String()
+		req.Start = &s
+	}
+	m.status = "Moved to " + strings.Join(append([]string{date}, fields[1:]...), " ")
+	return m.update("reschedule", m.target, req)
+}
+
+// moveTo changes a task's date, clearing its start time when clearStart
+// is set or it goes to the braindump
+func (m *Model) moveTo(task models.Task, date string, clearStart bool) Cmd {
+	if date == task.GetDateString() {
+		return nil
+	}
+	req := &models.UpdateTaskRequest{Date: &date}
+	if clearStart || date == "" {
+		empty := ""
+		req.Start = &empty
+	}
+	if date == "" {
+		m.status = "Moved to braindump"
+	} else {
+		m.status = "Moved to " + date
+	}
+	return m.update("reschedule", task, req)
+}
+
+// update applies req to the local copy of task at once and returns the
+// Cmd sending it to the API
+func (m *Model) update(action string, task models.Task, req *models.UpdateTaskRequest) Cmd {
+	if m.inflight[task.ID] == 0 {
+		m.saved[task.ID] = task
+	}
+	after := applyUpdate(task, req)
+	m.pending[task.ID] = after
+	m.inflight[task.ID]++
+	m.place(after)
+	m.clamp()
+
+	client := m.client
+	completeOnly := req.Complete != nil && *req.Complete && req.Description == nil && req.Date == nil
+	return func() Msg {
+		var err error
+		if completeOnly {
+			_, err = client.MarkTaskComplete(task.ID)
+		} else {
+			_, err = client.UpdateTask(task.ID, req)
+		}
+		return savedMsg{action: action, before: task, req: req, err: err}
+	}
+}
+
+// applyUpdate returns task with the fields set in req changed
+func applyUpdate(task models.Task, req *models.UpdateTaskRequest) models.Task {
+	if req.Description != nil {
+		task.Description = *req.Description
+	}
+	if req.Date != nil {
+		task.Date = rawString(*req.Date)
+	}
+	if req.Start != nil {
+		task.Start = rawString(*req.Start)
+	}
+	if req.Complete != nil {
+		task.Complete = *req.Complete
+	}
+	return task
+}
+
+// place puts the local copy of a task where its date says: the agenda if
+// it is on the day shown, the braindump if it has no date. Tasks in the
+// list pane are updated in place.
+func (m *Model) place(task models.Task) {
+	for _, p := range []Pane{PaneAgenda, PaneBraindump} {
+		m.panes[p].tasks = without(m.panes[p].tasks, task.ID)
+	}
+
+	switch task.GetDateString() {
+	case "":
+		m.panes[PaneBraindump].tasks = append(m.panes[PaneBraindump].tasks, task)
+	case m.date:
+		m.setAgenda(append(m.panes[PaneAgenda].tasks, task))
+	}
+
+	list := m.panes[PaneLists].tasks
+	for i := range list {
+		if list[i].ID == task.ID {
+			list[i] = task
+		}
+	}
+}
+
+func (m *Model) reapply() {
+	for _, task := range m.pending {
+		m.place(task)
+	}
+}
+
+// setAgenda stores the agenda with timed tasks first in start order
+func (m *Model) setAgenda(tasks []models.Task) {
+	planner.SortByStart(tasks, m.opts.Location)
+	m.panes[PaneAgenda].tasks = tasks
+}
+
+func (m *Model) showDate(date string) Cmd {
+	if date == m.date {
+		return nil
+	}
+	m.date = date
+	m.panes[PaneAgenda] = pane{}
+	m.reapply()
+
+	client := m.client
+	return func() Msg {
+		tasks, err := agenda(client, date)
+		return agendaMsg{date: date, tasks: tasks, err: err}
+	}
+}
+
+func (m *Model) showList(delta int) Cmd {
+	if len(m.lists) == 0 {
+		return nil
+	}
+	m.list = (m.list + delta + len(m.lists)) % len(m.lists)
+	m.panes[PaneLists] = pane{}
+	m.focus = PaneLists
+
+	client, listID := m.client, m.listID()
+	return func() Msg {
+		tasks, err := client.GetTasksByList(listID)
+		return listMsg{listID: listID, tasks: tasks, err: err}
+	}
+}
+
+func (m *Model) loadAll() Cmd {
+	client, date, listID := m.client, m.date, m.listID()
+	return func() Msg {
+		msg := loadedMsg{date: date}
+		if msg.agenda, msg.err = agenda(client, date); msg.err != nil {
+			return msg
+		}
+		if msg.braindump, msg.err = client.GetBraindump(); msg.err != nil {
+			return msg
+		}
+		if msg.lists, msg.err = client.GetLists(); msg.err != nil {
+			return msg
+		}
+		if listID == "" && len(msg.lists) > 0 {
+			listID = msg.lists[0].ID
+		}
+		if listID != "" {
+			msg.listID = listID
+			msg.listTasks, msg.err = client.GetTasksByList(listID)
+		}
+		return msg
+	}
+}
+
+// agenda fetches a day's tasks, stamping recurring instances that come
+// without a date with the day
+func agenda(client Client, date string) ([]models.Task, error) {
+	tasks, err := client.GetTasksForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].GetDateString() == "" {
+			tasks[i].Date = rawString(date)
+		}
+	}
+	return tasks, nil
+}
+
+// visible returns the tasks of a pane that match the search
+func (m *Model) visible(p Pane) []models.Task {
+	tasks := m.panes[p].tasks
+	if m.query == "" {
+		return tasks
+	}
+	query := strings.ToLower(m.query)
+	var matches []models.Task
+	for _, t := range tasks {
+		if strings.Contains(strings.ToLower(t.Description), query) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+func (m *Model) selected() (models.Task, bool) {
+	tasks := m.visible(m.focus)
+	cursor := m.panes[m.focus].cursor
+	if cursor < 0 || cursor >= len(tasks) {
+		return models.Task{}, false
+	}
+	return tasks[cursor], true
+}
+
+func (m *Model) selectID(p Pane, id string) {
+	for i, t := range m.visible(p) {
+		if t.ID == id {
+			m.panes[p].cursor = i
+			return
+		}
+	}
+}
+
+// clamp keeps every cursor on a visible task
+func (m *Model) clamp() {
+	for p := range m.panes {
+		n := len(m.visible(Pane(p)))
+		c := &m.panes[p].cursor
+		if *c >= n {
+			*c = n - 1
+		}
+		if *c < 0 {
+			*c = 0
+		}
+	}
+}
+
+func (m *Model) listID() string {
+	if m.list < len(m.lists) {
+		return m.lists[m.list].ID
+	}
+	return ""
+}
+
+func (m *Model) now() time.Time {
+	return m.opts.Now().In(m.opts.Location)
+}
+
+func (m *Model) today() string {
+	return dateutil.Format(m.now())
+}
+
+func (m *Model) shift(date string, days int) string {
+	d, err := time.ParseInLocation(dateutil.Layout, date, m.opts.Location)
+	if err != nil {
+		return date
+	}
+	return dateutil.Format(d.AddDate(0, 0, days))
+}
+
+func without(tasks []models.Task, id string) []models.Task {
+	kept := tasks[:0:0]
+	for _, t := range tasks {
+		if t.ID != id {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// rawString encodes s as a JSON string, or nil for an empty string
+func rawString(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	data, _ := json.Marshal(s)
+	return data
+}
+
+// batch runs cmds one after another and returns the results together
+func batch(cmds ...Cmd) Cmd {
+	var run []Cmd
+	for _, c := range cmds {
+		if c != nil {
+			run = append(run, c)
+		}
+	}
+	if len(run) == 0 {
+		return nil
+	}
+	return func() Msg {
+		msgs := make(batchMsg, len(run))
+		for i, c := range run {
+			msgs[i] = c()
+		}
+		return msgs
+	}
+}
+
+// batchMsg carries the results of a batch
+type batchMsg []Msg
@@ -0,0 +1,322 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+)
+
+// fakeClient keeps tasks in memory and applies updates like the API
+type fakeClient struct {
+	tasks    []models.Task
+	lists    []models.List
+	fail     bool
+	failures int // fail this many requests, then succeed
+	updates  []string
+}
+
+func (c *fakeClient) GetTasksForDate(date string) ([]models.Task, error) {
+	var tasks []models.Task
+	for _, t := range c.tasks {
+		if t.GetDateString() == date {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (c *fakeClient) GetBraindump() ([]models.Task, error) {
+	return c.GetTasksForDate("")
+}
+
+func (c *fakeClient) GetLists() ([]models.List, error) {
+	return c.lists, nil
+}
+
+func (c *fakeClient) GetTasksByList(listID string) ([]models.Task, error) {
+	var tasks []models.Task
+	for _, t := range c.tasks {
+		if t.ListID != nil && *t.ListID == listID {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (c *fakeClient) UpdateTask(taskID string, req *models.UpdateTaskRequest) (*models.Task, error) {
+	data, _ := json.Marshal(req)
+	c.updates = append(c.updates, taskID+" "+string(data))
+	if c.fail || c.failures > 0 {
+		c.failures--
+		return nil, errors.New("server error")
+	}
+	for i := range c.tasks {
+		t := &c.tasks[i]
+		if t.ID != taskID {
+			continue
+		}
+		if req.Description != nil {
+			t.Description = *req.Description
+		}
+		if req.Date != nil {
+			t.Date = rawString(*req.Date)
+		}
+		if req.Start != nil {
+			t.Start = rawString(*req.Start)
+		}
+		if req.Complete != nil {
+			t.Complete = *req.Complete
+		}
+		return t, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (c *fakeClient) MarkTaskComplete(taskID string) (*models.Task, error) {
+	done := true
+	return c.UpdateTask(taskID, &models.UpdateTaskRequest{Complete: &done})
+}
+
+func newTask(id, desc, date, start string) models.Task {
+	return models.Task{ID: id, Description: desc, Date: rawString(date), Start: rawString(start)}
+}
+
+func newModel(t *testing.T) (*Model, *fakeClient, *[]string) {
+	t.Helper()
+	list := "L1"
+	client := &fakeClient{
+		tasks: []models.Task{
+			newTask("t1", "Write report", "2024-01-15", ""),
+			newTask("t2", "Standup", "2024-01-15", "09:00"),
+			newTask("t3", "Idea", "", ""),
+			newTask("t4", "Client call", "2024-01-16", "14:00"),
+		},
+		lists: []models.List{{ID: "L1", Title: "Clients"}},
+	}
+	client.tasks[3].ListID = &list
+
+	var changes []string
+	m := New(client, Options{
+		Date:     "2024-01-15",
+		Location: time.UTC,
+		Now:      func() time.Time { return time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC) },
+		OnChange: func(action string, before models.Task) {
+			changes = append(changes, action+" "+before.ID)
+		},
+	})
+	run(m, m.Init())
+	return m, client, &changes
+}
+
+// run executes a Cmd and everything following from it
+func run(m *Model, cmd Cmd) {
+	for cmd != nil {
+		cmd = m.Update(cmd())
+	}
+}
+
+// press sends keys and returns the Cmd of the last one
+func press(m *Model, keys ...string) Cmd {
+	var cmd Cmd
+	for _, k := range keys {
+		cmd = m.Update(KeyMsg(k))
+	}
+	return cmd
+}
+
+func ids(tasks []models.Task) string {
+	s := make([]string, len(tasks))
+	for i, t := range tasks {
+		s[i] = t.ID
+	}
+	return strings.Join(s, ",")
+}
+
+func TestLoad(t *testing.T) {
+	m, _, _ := newModel(t)
+
+	if got := ids(m.visible(PaneAgenda)); got != "t2,t1" {
+		t.Errorf("expected timed tasks first in the agenda, got %s", got)
+	}
+	if got := ids(m.visible(PaneBraindump)); got != "t3" {
+		t.Errorf("unexpected braindump %s", got)
+	}
+	if got := ids(m.visible(PaneLists)); got != "t4" {
+		t.Errorf("unexpected list tasks %s", got)
+	}
+
+	press(m, "j", "j", "j")
+	if task, _ := m.selected(); task.ID != "t1" {
+		t.Errorf("expected the cursor to stop at t1, got %s", task.ID)
+	}
+	press(m, "tab")
+	if task, _ := m.selected(); m.focus != PaneBraindump || task.ID != "t3" {
+		t.Errorf("expected t3 in the braindump, got %s", task.ID)
+	}
+}
+
+func TestCompleteIsOptimistic(t *testing.T) {
+	m, client, changes := newModel(t)
+
+	cmd := press(m, "x")
+	if task, _ := m.selected(); !task.Complete {
+		t.Error("expected the task to be completed before the request")
+	}
+	if len(client.updates) != 0 {
+		t.Errorf("expected no request yet, got %v", client.updates)
+	}
+
+	run(m, cmd)
+	if len(client.updates) != 1 || client.updates[0] != `t2 {"complete":true}` {
+		t.Errorf("unexpected requests %v", client.updates)
+	}
+	if strings.Join(*changes, ";") != "complete t2" {
+		t.Errorf("unexpected changes %v", *changes)
+	}
+}
+
+func TestFailedUpdateRestoresTask(t *testing.T) {
+	m, client, changes := newModel(t)
+	client.fail = true
+
+	run(m, press(m, "j", "b"))
+	if got := ids(m.visible(PaneAgenda)); got != "t2,t1" {
+		t.Errorf("expected t1 back in the agenda, got %s", got)
+	}
+	if got := ids(m.visible(PaneBraindump)); got != "t3" {
+		t.Errorf("expected t1 out of the braindump, got %s", got)
+	}
+	if !strings.Contains(m.status, "Failed to reschedule") || len(*changes) != 0 {
+		t.Errorf("unexpected status %q and changes %v", m.status, *changes)
+	}
+}
+
+func TestFailedUpdateKeepsLaterChange(t *testing.T) {
+	m, client, changes := newModel(t)
+	client.failures = 1
+
+	complete := press(m, "x")
+	move := press(m, "b")
+	run(m, complete)
+	run(m, move)
+
+	if got := ids(m.visible(PaneAgenda)); got != "t1" {
+		t.Errorf("expected t2 to stay out of the agenda, got %s", got)
+	}
+	if got := ids(m.visible(PaneBraindump)); got != "t3,t2" {
+		t.Errorf("expected t2 in the braindump, got %s", got)
+	}
+	if len(client.updates) != 2 || strings.Join(*changes, ";") != "reschedule t2" {
+		t.Errorf("unexpected requests %v and changes %v", client.updates, *changes)
+	}
+}
+
+func TestFailedUpdateIsDroppedFromLaterChange(t *testing.T) {
+	m, client, _ := newModel(t)
+	client.failures = 1
+
+	var befores []models.Task
+	m.opts.OnChange = func(action string, before models.Task) {
+		befores = append(befores, before)
+	}
+
+	rename := press(m, "e", "ctrl-u", "N", "e", "w", "enter")
+	complete := press(m, "x")
+	run(m, rename)
+	run(m, complete)
+
+	task, _ := m.selected()
+	if task.ID != "t2" || task.Description != "Standup" || !task.Complete {
+		t.Errorf("expected t2 completed under its old name, got %+v", task)
+	}
+	if len(befores) != 1 || befores[0].Description != "Standup" || befores[0].Complete {
+		t.Errorf("expected the undo state without the failed rename, got %+v", befores)
+	}
+}
+
+func TestMoveFollowsTask(t *testing.T) {
+	m, client, _ := newModel(t)
+
+	cmd := press(m, "L")
+	if m.date != "2024-01-16" {
+		t.Fatalf("expected the agenda to follow to 2024-01-16, got %s", m.date)
+	}
+	if task, _ := m.selected(); task.ID != "t2" {
+		t.Errorf("expected t2 to stay selected, got %s", task.ID)
+	}
+
+	run(m, cmd)
+	if got := ids(m.visible(PaneAgenda)); got != "t2,t4" {
+		t.Errorf("unexpected agenda %s", got)
+	}
+	if client.updates[0] != `t2 {"date":"2024-01-16"}` {
+		t.Errorf("unexpected requests %v", client.updates)
+	}
+}
+
+func TestRescheduleAndEdit(t *testing.T) {
+	m, client, _ := newModel(t)
+
+	run(m, press(m, "j", "r", "ctrl-u", "t", "o", "m", "o", "r", "r", "o", "w", " ", "1", "0", ":", "3", "0", "enter"))
+	if got := ids(m.visible(PaneAgenda)); got != "t2" {
+		t.Errorf("expected t1 to leave the agenda, got %s", got)
+	}
+	if client.updates[0] != `t1 {"date":"2024-01-16","start":"10:30"}` {
+		t.Errorf("unexpected requests %v", client.updates)
+	}
+
+	press(m, "tab")
+	run(m, press(m, "e", "ctrl-u", "N", "e", "w", "enter"))
+	if task, _ := m.selected(); task.Description != "New" {
+		t.Errorf("expected the description to change, got %q", task.Description)
+	}
+
+	run(m, press(m, "shift-tab", "r", "ctrl-u", "enter"))
+	if got := ids(m.visible(PaneBraindump)); got != "t3,t2" {
+		t.Errorf("expected t2 in the braindump, got %s", got)
+	}
+	if client.updates[len(client.updates)-1] != `t2 {"date":"","start":""}` {
+		t.Errorf("unexpected requests %v", client.updates)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m, _, _ := newModel(t)
+
+	press(m, "/", "R", "E", "P")
+	if got := ids(m.visible(PaneAgenda)); got != "t1" {
+		t.Errorf("expected the search to match t1, got %s", got)
+	}
+	press(m, "enter")
+	if m.mode != modeNormal || m.query != "REP" {
+		t.Errorf("expected the search to stay, got %q", m.query)
+	}
+	press(m, "esc")
+	if got := ids(m.visible(PaneAgenda)); got != "t2,t1" {
+		t.Errorf("expected the search to clear, got %s", got)
+	}
+}
+
+func TestView(t *testing.T) {
+	m, _, _ := newModel(t)
+
+	lines := m.View(90, 8)
+	if len(lines) != 8 {
+		t.Fatalf("expected 8 lines, got %d", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"Mon 2024-01-15 (today)", "Agenda (2)", "Braindump (1)", "Clients 1/1 (1)", "[ ] 09:00 Standup", "[ ] Client call · 2024-01-16"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected %q on screen:\n%s", want, screen)
+		}
+	}
+
+	narrow := strings.Join(m.View(40, 8), "\n")
+	if strings.Contains(narrow, "Braindump") {
+		t.Errorf("expected only the focused pane on a narrow screen:\n%s", narrow)
+	}
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/term"
+)
+
+// Run shows m full-screen on the terminal attached to in and out until
+// the user quits. Cmds run one at a time in the order they were issued,
+// so requests for the same task reach the API in order.
+func Run(m *Model, in, out *os.File) error {
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return fmt.Errorf("the interface needs a terminal")
+	}
+
+	restore, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer restore()
+
+	w := bufio.NewWriter(out)
+	fmt.Fprint(w, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(w, "\x1b[?25h\x1b[?1049l")
+		w.Flush()
+	}()
+
+	msgs := make(chan Msg, 256)
+	cmds := make(chan Cmd, 256)
+	defer close(cmds)
+
+	go func() {
+		for cmd := range cmds {
+			msgs <- cmd()
+		}
+	}()
+
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				msgs <- KeyMsg("ctrl-c")
+				return
+			}
+			for _, k := range parseKeys(buf[:n]) {
+				msgs <- KeyMsg(k)
+			}
+		}
+	}()
+
+	// The size is polled, which also works where SIGWINCH does not exist
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	if cmd := m.Init(); cmd != nil {
+		cmds <- cmd
+	}
+	width, height := size(out)
+	render(w, m, width, height)
+
+	for !m.Done() {
+		select {
+		case msg := <-msgs:
+			if cmd := m.Update(msg); cmd != nil {
+				cmds <- cmd
+			}
+		case <-ticker.C:
+			if nw, nh := size(out); nw != width || nh != height {
+				width, height = nw, nh
+			} else {
+				continue
+			}
+		}
+		render(w, m, width, height)
+	}
+
+	// Wait for changes still on their way to the API
+	deadline := time.After(10 * time.Second)
+	for m.Saving() {
+		select {
+		case msg := <-msgs:
+			if _, ok := msg.(KeyMsg); !ok {
+				m.Update(msg)
+			}
+		case <-deadline:
+			return fmt.Errorf("gave up waiting for %d change(s) to be saved", len(m.inflight))
+		}
+	}
+	return nil
+}
+
+func size(f *os.File) (int, int) {
+	if width, height, ok := term.Size(f); ok {
+		return width, height
+	}
+	return term.DefaultWidth, 24
+}
+
+// render redraws the whole screen from the top left corner
+func render(w *bufio.Writer, m *Model, width, height int) {
+	lines := m.View(width, height)
+	fmt.Fprint(w, "\x1b[H")
+	fmt.Fprint(w, strings.Join(lines, "\x1b[K\r\n"))
+	fmt.Fprint(w, "\x1b[K\x1b[J")
+	w.Flush()
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+const (
+	reset   = "\x1b[0m"
+	bold    = "\x1b[1m"
+	dim     = "\x1b[2m"
+	reverse = "\x1b[7m"
+)
+
+// minPaneWidth is the narrowest a pane gets before only the focused one
+// is shown
+const minPaneWidth = 24
+
+const help = "j/k move  tab pane  x done  e edit  r reschedule  H/L day  m to agenda  b braindump  </> date  [/] list  / search  R reload  q quit"
+
+// View renders the interface as height lines of at most width columns
+func (m *Model) View(width, height int) []string {
+	if width < 10 || height < 4 {
+		return []string{"Terminal too small"}
+	}
+
+	lines := make([]string, 0, height)
+	title := " Ellie  " + m.dayTitle()
+	if m.query != "" && m.mode != modeSearch {
+		title += "  search: " + m.query
+	}
+	lines = append(lines, bold+fit(title, width)+reset)
+
+	panes := []Pane{PaneAgenda, PaneBraindump, PaneLists}
+	if width < paneCount*minPaneWidth+paneCount-1 {
+		panes = []Pane{m.focus}
+	}
+	paneWidth := (width - (len(panes) - 1)) / len(panes)
+
+	rows := height - 3
+	columns := make([][]string, len(panes))
+	for i, p := range panes {
+		w := paneWidth
+		if i == len(panes)-1 {
+			w = width - (paneWidth+1)*(len(panes)-1)
+		}
+		columns[i] = m.paneView(p, w, rows)
+	}
+	for r := 0; r < rows; r++ {
+		parts := make([]string, len(columns))
+		for i, column := range columns {
+			parts[i] = column[r]
+		}
+		lines = append(lines, strings.Join(parts, dim+"│"+reset))
+	}
+
+	lines = append(lines, m.statusLine(width), dim+fit(help, width)+reset)
+	return lines
+}
+
+func (m *Model) dayTitle() string {
+	day, err := time.ParseInLocation(dateutil.Layout, m.date, m.opts.Location)
+	if err != nil {
+		return m.date
+	}
+	title := day.Format("Mon 2006-01-02")
+	if m.date == m.today() {
+		title += " (today)"
+	}
+	return title
+}
+
+// paneView renders a pane's header and rows, scrolled to the cursor
+func (m *Model) paneView(p Pane, width, rows int) []string {
+	tasks := m.visible(p)
+	pn := &m.panes[p]
+
+	header := fmt.Sprintf(" %s (%d)", m.paneTitle(p), len(tasks))
+	style := bold
+	if p == m.focus {
+		style = bold + reverse
+	}
+	lines := []string{style + fit(header, width) + reset}
+
+	body := rows - 1
+	if pn.cursor < pn.offset {
+		pn.offset = pn.cursor
+	}
+	if pn.cursor >= pn.offset+body {
+		pn.offset = pn.cursor - body + 1
+	}
+
+	for i := pn.offset; i < pn.offset+body; i++ {
+		if i >= len(tasks) {
+			lines = append(lines, strings.Repeat(" ", width))
+			continue
+		}
+		line := fit(" "+m.taskLine(p, &tasks[i]), width)
+		switch {
+		case p == m.focus && i == pn.cursor:
+			line = reverse + line + reset
+		case tasks[i].Complete:
+			line = dim + line + reset
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (m *Model) paneTitle(p Pane) string {
+	switch p {
+	case PaneAgenda:
+		return "Agenda"
+	case PaneBraindump:
+		return "Braindump"
+	default:
+		if len(m.lists) == 0 {
+			return "Lists"
+		}
+		return fmt.Sprintf("%s %d/%d", m.lists[m.list].Title, m.list+1, len(m.lists))
+	}
+}
+
+func (m *Model) taskLine(p Pane, task *models.Task) string {
+	var sb strings.Builder
+	if task.Complete {
+		sb.WriteString("[x] ")
+	} else {
+		sb.WriteString("[ ] ")
+	}
+	if start, ok := dateutil.ParseStart(task.GetDateString(), task.GetStartString(), m.opts.Location); ok && p == PaneAgenda {
+		sb.WriteString(start.Format("15:04") + " ")
+	}
+	sb.WriteString(strings.Join(strings.Fields(task.Description), " "))
+	if minutes := planner.Estimate(task); minutes > 0 {
+		sb.WriteString(" (" + dateutil.FormatMinutes(minutes) + ")")
+	}
+	if date := task.GetDateString(); p == PaneLists && date != "" {
+		sb.WriteString(" · " + date)
+	}
+	return sb.String()
+}
+
+func (m *Model) statusLine(width int) string {
+	input := string(m.input) + "█"
+	switch m.mode {
+	case modeSearch:
+		return fit("/"+input, width)
+	case modeEdit:
+		return fit("Edit: "+input, width)
+	case modeReschedule:
+		return fit("Move to (date [HH:MM], empty for braindump): "+input, width)
+	}
+	return fit(m.status, width)
+}
+
+// fit cuts s to width runes, ending in an ellipsis, or pads it with
+// spaces
+func fit(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		if width <= 0 {
+			return ""
+		}
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
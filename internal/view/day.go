@@ -0,0 +1,100 @@
+package view
+
+import (
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+// gutter is the width of the time column of the day timeline
+const gutter = 8 // "00:00 │ "
+
+// Day renders date as an hour timeline. Timed tasks take one row per
+// Slot of their estimate, overlapping tasks share the width side by
+// side, and tasks without a start time are listed below.
+func Day(date string, tasks []models.Task, opts Options) []string {
+	opts.defaults()
+
+	var timed, untimed []entry
+	for _, e := range entries(date, tasks, opts.Location) {
+		if e.timed {
+			timed = append(timed, e)
+		} else {
+			untimed = append(untimed, e)
+		}
+	}
+
+	slot := planner.Clock(opts.Slot)
+	from, to := opts.Hours.Start/60*60, opts.Hours.End
+	for _, e := range timed {
+		from = min(from, e.start/60*60)
+		to = max(to, max(e.end, e.start+slot))
+	}
+	to = min((to+59)/60*60, 24*60)
+	rows := int((to - from + slot - 1) / slot)
+
+	// Assign each task the first lane that is free when it starts
+	type block struct {
+		entry
+		row, height, lane int
+	}
+	var blocks []block
+	var laneEnds []int
+	for _, e := range timed {
+		b := block{entry: e, row: int((e.start - from) / slot)}
+		b.height = int((max(e.end, e.start+1)-from+slot-1)/slot) - b.row
+		for b.lane < len(laneEnds) && laneEnds[b.lane] > b.row {
+			b.lane++
+		}
+		if b.lane == len(laneEnds) {
+			laneEnds = append(laneEnds, 0)
+		}
+		laneEnds[b.lane] = b.row + b.height
+		blocks = append(blocks, b)
+	}
+
+	day, _ := time.ParseInLocation("2006-01-02", date, opts.Location)
+	lines := []string{day.Format("Monday 2006-01-02"), ""}
+
+	c := newCanvas(rows, opts.Width)
+	for r := 0; r < rows; r++ {
+		at := from + planner.Clock(r)*slot
+		if at%60 == 0 {
+			c.put(r, 0, at.String(), 5, "")
+		}
+		c.put(r, gutter-2, "│", 1, "")
+	}
+
+	laneWidth := opts.Width - gutter
+	if len(laneEnds) > 1 {
+		laneWidth = (opts.Width - gutter) / len(laneEnds)
+	}
+	for _, b := range blocks {
+		style := opts.style(b.task)
+		col := gutter + b.lane*laneWidth
+		for r := b.row; r < b.row+b.height; r++ {
+			c.put(r, col, "┃", 1, style)
+		}
+		c.put(b.row, col+2, b.start.String()+" "+label(b.task), laneWidth-3, style)
+		if minutes := planner.Estimate(b.task); minutes > 0 && b.height > 1 {
+			c.put(b.row+1, col+2, dateutil.FormatMinutes(minutes), laneWidth-3, style)
+		}
+	}
+	lines = append(lines, c.lines()...)
+
+	if len(untimed) > 0 {
+		lines = append(lines, "", "Anytime")
+		u := newCanvas(len(untimed), opts.Width)
+		for i, e := range untimed {
+			text := "• " + label(e.task)
+			if minutes := planner.Estimate(e.task); minutes > 0 {
+				text += " (" + dateutil.FormatMinutes(minutes) + ")"
+			}
+			u.put(i, 2, text, opts.Width-2, opts.style(e.task))
+		}
+		lines = append(lines, u.lines()...)
+	}
+	return lines
+}
@@ -0,0 +1,179 @@
+// Package view renders tasks as a day timeline or a week grid for the
+// terminal
+package view
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+const (
+	reset = "\x1b[0m"
+	bold  = "\x1b[1m"
+	dim   = "\x1b[2m"
+)
+
+// Options controls rendering
+type Options struct {
+	// Width is the number of terminal columns to fill
+	Width int
+	// Color enables ANSI colors taken from LabelColors
+	Color bool
+	// LabelColors maps label IDs to hex colors such as "#ff8800"
+	LabelColors map[string]string
+	Location    *time.Location
+	// Hours is always shown on the day timeline, which grows to fit
+	// tasks outside it
+	Hours planner.Window
+	// Slot is the number of minutes per row of the day timeline
+	Slot int
+	// Today is highlighted in the week grid
+	Today string
+}
+
+func (o *Options) defaults() {
+	if o.Width < 20 {
+		o.Width = 20
+	}
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+	if o.Hours.End <= o.Hours.Start {
+		o.Hours = planner.Window{Start: 8 * 60, End: 18 * 60}
+	}
+	if o.Slot <= 0 {
+		o.Slot = 30
+	}
+}
+
+// ParseHex parses a color such as "#ff8800", "ff8800" or "#f80"
+func ParseHex(s string) (r, g, b uint8, ok bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
+
+// style returns the escape sequence a task is drawn with
+func (o *Options) style(task *models.Task) string {
+	if !o.Color {
+		return ""
+	}
+	var s string
+	if task.Label != nil {
+		if r, g, b, ok := ParseHex(o.LabelColors[*task.Label]); ok {
+			s = fmt.Sprintf("\x1b[38;2;%d;%d;%dm", r, g, b)
+		}
+	}
+	if task.Complete {
+		s += dim
+	}
+	return s
+}
+
+// entry is a task placed on a day
+type entry struct {
+	task       *models.Task
+	start, end planner.Clock
+	timed      bool
+}
+
+// entries returns the tasks on date, timed ones first in start order
+func entries(date string, tasks []models.Task, loc *time.Location) []entry {
+	sorted := append([]models.Task(nil), tasks...)
+	planner.SortByStart(sorted, loc)
+
+	var timed, untimed []entry
+	for i := range sorted {
+		task := &sorted[i]
+		if start, ok := dateutil.ParseStart(task.GetDateString(), task.GetStartString(), loc); ok && dateutil.Format(start) == date {
+			c := planner.ClockOf(start)
+			timed = append(timed, entry{task: task, start: c, end: c + planner.Clock(planner.Estimate(task)), timed: true})
+		} else if task.GetDateString() == date {
+			untimed = append(untimed, entry{task: task})
+		}
+	}
+	return append(timed, untimed...)
+}
+
+// label is a task's description with a mark for completed tasks
+func label(task *models.Task) string {
+	desc := strings.Join(strings.Fields(task.Description), " ")
+	if task.Complete {
+		return "✓ " + desc
+	}
+	return desc
+}
+
+type cell struct {
+	r     rune
+	style string
+}
+
+// canvas is a grid of styled characters
+type canvas [][]cell
+
+func newCanvas(rows, cols int) canvas {
+	c := make(canvas, rows)
+	for i := range c {
+		c[i] = make([]cell, cols)
+		for j := range c[i] {
+			c[i][j].r = ' '
+		}
+	}
+	return c
+}
+
+// put writes s at row, col, cut off with an ellipsis after width cells
+func (c canvas) put(row, col int, s string, width int, style string) {
+	if row < 0 || row >= len(c) || width <= 0 {
+		return
+	}
+	runes := []rune(s)
+	if len(runes) > width {
+		runes = append(runes[:width-1], '…')
+	}
+	for i, r := range runes {
+		if col+i >= 0 && col+i < len(c[row]) {
+			c[row][col+i] = cell{r: r, style: style}
+		}
+	}
+}
+
+// lines renders the canvas, switching styles only where they change
+func (c canvas) lines() []string {
+	out := make([]string, len(c))
+	for i, row := range c {
+		var sb strings.Builder
+		current := ""
+		for _, cl := range row {
+			if cl.style != current {
+				if current != "" {
+					sb.WriteString(reset)
+				}
+				sb.WriteString(cl.style)
+				current = cl.style
+			}
+			sb.WriteRune(cl.r)
+		}
+		if current != "" {
+			sb.WriteString(reset)
+		}
+		out[i] = strings.TrimRight(sb.String(), " ")
+	}
+	return out
+}
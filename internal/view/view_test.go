@@ -0,0 +1,121 @@
+package view
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+func task(id, date, start string, minutes int) models.Task {
+	t := models.Task{ID: id, Description: "Task " + id}
+	t.Date, _ = json.Marshal(date)
+	if start != "" {
+		t.Start, _ = json.Marshal(start)
+	}
+	if minutes > 0 {
+		seconds := minutes * 60
+		t.EstimatedTime = &seconds
+	}
+	return t
+}
+
+func TestParseHex(t *testing.T) {
+	if r, g, b, ok := ParseHex("#ff8800"); !ok || r != 255 || g != 136 || b != 0 {
+		t.Errorf("unexpected #ff8800: %d %d %d %v", r, g, b, ok)
+	}
+	if r, g, b, ok := ParseHex("f80"); !ok || r != 255 || g != 136 || b != 0 {
+		t.Errorf("unexpected f80: %d %d %d %v", r, g, b, ok)
+	}
+	for _, s := range []string{"", "red", "#12345", "#gggggg"} {
+		if _, _, _, ok := ParseHex(s); ok {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestDay(t *testing.T) {
+	done := task("c", "2024-01-15", "", 15)
+	done.Complete = true
+	tasks := []models.Task{
+		task("a", "2024-01-15", "09:00", 60),
+		task("b", "2024-01-15", "09:30", 30),
+		done,
+		task("other", "2024-01-16", "09:00", 60),
+	}
+
+	lines := Day("2024-01-15", tasks, Options{
+		Width:    40,
+		Location: time.UTC,
+		Hours:    planner.Window{Start: 9 * 60, End: 10 * 60},
+	})
+
+	want := []string{
+		"Monday 2024-01-15",
+		"",
+		"09:00 │ ┃ 09:00 Task a",
+		"      │ ┃ 1h            ┃ 09:30 Task b",
+		"",
+		"Anytime",
+		"  • ✓ Task c (15m)",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected day:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDayGrowsToFitTasks(t *testing.T) {
+	lines := Day("2024-01-15", []models.Task{task("late", "2024-01-15", "18:30", 45)}, Options{
+		Width:    30,
+		Location: time.UTC,
+		Hours:    planner.Window{Start: 17 * 60, End: 18 * 60},
+		Slot:     60,
+	})
+
+	if len(lines) != 5 || !strings.HasPrefix(lines[2], "17:00") || !strings.HasPrefix(lines[4], "19:00") {
+		t.Errorf("expected rows from 17:00 to 19:00, got %q", lines)
+	}
+	if !strings.Contains(lines[3], "18:30 Task late") {
+		t.Errorf("expected the task at 18:00, got %q", lines[3])
+	}
+}
+
+func TestWeek(t *testing.T) {
+	labelled := task("b", "2024-01-16", "", 30)
+	label := "l1"
+	labelled.Label = &label
+	tasks := []models.Task{
+		task("a", "2024-01-15", "09:00", 60),
+		labelled,
+	}
+
+	lines := Week([]string{"2024-01-15", "2024-01-16"}, tasks, Options{
+		Width:    31,
+		Location: time.UTC,
+		Today:    "2024-01-16",
+	})
+
+	want := []string{
+		"Mon 15         │Tue 16*",
+		"───────────────┼───────────────",
+		"09:00 Task a   │Task b",
+		"───────────────┼───────────────",
+		"1h             │30m",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected week:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	colored := Week([]string{"2024-01-16"}, tasks, Options{
+		Width:       20,
+		Location:    time.UTC,
+		Color:       true,
+		LabelColors: map[string]string{"l1": "#ff0000"},
+	})
+	if !strings.Contains(colored[2], "\x1b[38;2;255;0;0mTask b\x1b[0m") {
+		t.Errorf("expected Task b in red, got %q", colored[2])
+	}
+}
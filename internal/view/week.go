@@ -0,0 +1,79 @@
+package view
+
+import (
+	"strings"
+	"time"
+
+	"github.com/goldie/ellie-cli/internal/dateutil"
+	"github.com/goldie/ellie-cli/internal/models"
+	"github.com/goldie/ellie-cli/internal/planner"
+)
+
+// Week renders one column per date with the day's tasks, timed ones
+// first, and the total of the estimates at the bottom. Columns share
+// the width evenly.
+func Week(dates []string, tasks []models.Task, opts Options) []string {
+	opts.defaults()
+	if len(dates) == 0 {
+		return nil
+	}
+
+	width := (opts.Width - (len(dates) - 1)) / len(dates)
+	if width < 4 {
+		width = 4
+	}
+	total := width*len(dates) + len(dates) - 1
+
+	days := make([][]entry, len(dates))
+	rows := 0
+	for i, date := range dates {
+		days[i] = entries(date, tasks, opts.Location)
+		if len(days[i]) > rows {
+			rows = len(days[i])
+		}
+	}
+
+	// header, rule, tasks, rule, totals
+	c := newCanvas(rows+4, total)
+	for i, date := range dates {
+		col := i * (width + 1)
+		if i > 0 {
+			for r := range c {
+				sep := "│"
+				if r == 1 || r == rows+2 {
+					sep = "┼"
+				}
+				c.put(r, col-1, sep, 1, "")
+			}
+		}
+		c.put(1, col, strings.Repeat("─", width), width, "")
+		c.put(rows+2, col, strings.Repeat("─", width), width, "")
+
+		header, style := date, ""
+		if day, err := time.ParseInLocation("2006-01-02", date, opts.Location); err == nil {
+			header = day.Format("Mon 02")
+		}
+		if date == opts.Today {
+			if opts.Color {
+				style = bold
+			} else {
+				header += "*"
+			}
+		}
+		c.put(0, col, header, width, style)
+
+		planned := 0
+		for r, e := range days[i] {
+			text := label(e.task)
+			if e.timed {
+				text = e.start.String() + " " + text
+			}
+			c.put(r+2, col, text, width, opts.style(e.task))
+			planned += planner.Estimate(e.task)
+		}
+		if planned > 0 {
+			c.put(rows+3, col, dateutil.FormatMinutes(planned), width, "")
+		}
+	}
+	return c.lines()
+}